@@ -2,6 +2,65 @@
 
 package metamodel_
 
+import "fmt"
+
 const (
 	Comma string = ", "
 )
+
+// Struct tags a metamodel generated with several tags translates between.
+const (
+	TagJSON = "json"
+	TagBSON = "bson"
+	TagGORM = "gorm"
+)
+
+// TagNames holds, for every field of a struct, its name under each struct tag.
+// Nested document fields are listed with their dotted path.
+type TagNames []map[string]string
+
+// Translate maps the name of a field under one tag to its name under another.
+// Example: User_.TagNames.Translate(TagJSON, TagGORM, "emailAddress") → "email_address", true
+func (t TagNames) Translate(from, to, name string) (string, bool) {
+	for _, names := range t {
+		if names[from] == name && name != "" {
+			translated, ok := names[to]
+			return translated, ok && translated != ""
+		}
+	}
+	return "", false
+}
+
+// TranslateAll maps several names at once, reporting the first unknown one.
+// Example: sort parameters of an API request to their storage columns.
+func (t TagNames) TranslateAll(from, to string, names ...string) ([]string, error) {
+	translated := make([]string, 0, len(names))
+	for _, name := range names {
+		n, ok := t.Translate(from, to, name)
+		if !ok {
+			return nil, fmt.Errorf("no %s name for %s field %q", to, from, name)
+		}
+		translated = append(translated, n)
+	}
+	return translated, nil
+}
+
+// JSONToColumn maps a json field name to its gorm column.
+func (t TagNames) JSONToColumn(name string) (string, bool) {
+	return t.Translate(TagJSON, TagGORM, name)
+}
+
+// ColumnToJSON maps a gorm column to its json field name.
+func (t TagNames) ColumnToJSON(name string) (string, bool) {
+	return t.Translate(TagGORM, TagJSON, name)
+}
+
+// JSONToBSON maps a json field name to its bson key.
+func (t TagNames) JSONToBSON(name string) (string, bool) {
+	return t.Translate(TagJSON, TagBSON, name)
+}
+
+// BSONToJSON maps a bson key to its json field name.
+func (t TagNames) BSONToJSON(name string) (string, bool) {
+	return t.Translate(TagBSON, TagJSON, name)
+}
@@ -3,17 +3,36 @@
 
 package metamodel_
 
-// Entity_ contains field name constants for Entity
-var Entity_ = struct {
+import (
+	"github.com/gofrs/uuid"
+	"time"
+)
+
+// EntityMetamodel is the type of Entity_.
+type EntityMetamodel struct {
 	TableName string
-	Id        Field
-	Uuid      Field
-	CreatedAt Field
-	UpdatedAt Field
-}{
-	TableName: "entitys",
-	Id:        Field{FieldName: "id", TableName: "entitys"},
-	Uuid:      Field{FieldName: "uuid", TableName: "entitys"},
-	CreatedAt: Field{FieldName: "created_at", TableName: "entitys"},
-	UpdatedAt: Field{FieldName: "updated_at", TableName: "entitys"},
+	Id        NumberField[uint]
+	Uuid      Field[uuid.UUID]
+	CreatedAt TimeField[time.Time]
+	UpdatedAt TimeField[time.Time]
+}
+
+// As returns a copy of Entity_ whose fields are qualified by alias, for
+// self-joins and queries that reference the table more than once.
+// Example: Entity_.As("t")
+func (m EntityMetamodel) As(alias string) EntityMetamodel {
+	m.Id = m.Id.WithOwner(alias)
+	m.Uuid = m.Uuid.WithOwner(alias)
+	m.CreatedAt = m.CreatedAt.WithOwner(alias)
+	m.UpdatedAt = m.UpdatedAt.WithOwner(alias)
+	return m
+}
+
+// Entity_ contains field name constants for Entity
+var Entity_ = EntityMetamodel{
+	TableName: "entities",
+	Id:        NumberField[uint]{Field: Field[uint]{FieldName: "id", TableName: "entities"}},
+	Uuid:      Field[uuid.UUID]{FieldName: "uuid", TableName: "entities"},
+	CreatedAt: TimeField[time.Time]{Field: Field[time.Time]{FieldName: "created_at", TableName: "entities"}},
+	UpdatedAt: TimeField[time.Time]{Field: Field[time.Time]{FieldName: "updated_at", TableName: "entities"}},
 }
@@ -3,15 +3,69 @@
 
 package metamodel_
 
-// Feature_ contains field name constants for Feature
-var Feature_ = struct {
+import (
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// FeatureMetamodel is the type of Feature_.
+type FeatureMetamodel struct {
 	TableName   string
-	FeatureName Field
-	ScenarioID  Field
-	Description Field
-}{
-	TableName:   "features",
-	FeatureName: Field{FieldName: "feature_name", TableName: "features"},
-	ScenarioID:  Field{FieldName: "scenario_id", TableName: "features"},
-	Description: Field{FieldName: "description", TableName: "features"},
+	TagNames    TagNames
+	FeatureName StringField[string]
+	ScenarioID  NumberField[int]
+	Status      StringField[string]
+	Description StringField[string]
+	IgnoreMe    StringField[string]
+	SkippedTag  StringField[string]
+}
+
+// As returns a copy of Feature_ whose fields are qualified by alias, for
+// self-joins and queries that reference the table more than once.
+// Example: Feature_.As("t")
+func (m FeatureMetamodel) As(alias string) FeatureMetamodel {
+	m.FeatureName = m.FeatureName.WithOwner(alias)
+	m.ScenarioID = m.ScenarioID.WithOwner(alias)
+	m.Status = m.Status.WithOwner(alias)
+	m.Description = m.Description.WithOwner(alias)
+	m.IgnoreMe = m.IgnoreMe.WithOwner(alias)
+	m.SkippedTag = m.SkippedTag.WithOwner(alias)
+	return m
+}
+
+// Feature_ contains field name constants for Feature
+var Feature_ = FeatureMetamodel{
+	TableName: "features",
+	TagNames: TagNames{
+		{"bson": "featurename", "json": "feature_name"},
+		{"bson": "scenarioid", "json": "scenario_id"},
+		{"bson": "status", "json": "Status"},
+		{"bson": "desc", "json": "description"},
+		{"bson": "ignoreme"},
+		{"bson": "skippedtag"},
+	},
+	FeatureName: StringField[string]{Field: Field[string]{FieldName: "feature_name", TableName: "features", BSON: "featurename", JSON: "feature_name"}},
+	ScenarioID:  NumberField[int]{Field: Field[int]{FieldName: "scenario_id", TableName: "features", BSON: "scenarioid", JSON: "scenario_id"}},
+	Status:      StringField[string]{Field: Field[string]{FieldName: "Status", TableName: "features", BSON: "status", JSON: "Status"}},
+	Description: StringField[string]{Field: Field[string]{FieldName: "description", TableName: "features", BSON: "desc", JSON: "description"}},
+	IgnoreMe:    StringField[string]{Field: Field[string]{FieldName: "ignoreme", TableName: "features", BSON: "ignoreme"}},
+	SkippedTag:  StringField[string]{Field: Field[string]{FieldName: "skippedtag", TableName: "features", BSON: "skippedtag"}},
+}
+
+func init() {
+	registerMgoIndexes(Feature_.TableName, Feature_.Indexes)
+}
+
+// Indexes returns the MongoDB indexes declared on Feature, created
+// in the Feature_.TableName collection by EnsureIndexes.
+func (m FeatureMetamodel) Indexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: m.Status.mgoKey(), Value: 1},
+			},
+			Options: options.Index().SetSparse(true),
+		},
+	}
 }
@@ -3,59 +3,109 @@
 
 package metamodel_
 
+import (
+	"github.com/gofrs/uuid"
+	"time"
+)
+
+// GormTestMetamodel is the type of GormTest_.
+type GormTestMetamodel struct {
+	TableName   string
+	Id          NumberField[uint]
+	Uuid        Field[uuid.UUID]
+	CreatedAt   TimeField[time.Time]
+	UpdatedAt   TimeField[time.Time]
+	FeatureName StringField[string]
+	Type        NumberField[int]
+	IsActive    BoolField[bool]
+	GormElement SliceField[any]
+	PriceUnit   StringField[string]
+}
+
+// As returns a copy of GormTest_ whose fields are qualified by alias, for
+// self-joins and queries that reference the table more than once.
+// Example: GormTest_.As("t")
+func (m GormTestMetamodel) As(alias string) GormTestMetamodel {
+	m.Id = m.Id.WithOwner(alias)
+	m.Uuid = m.Uuid.WithOwner(alias)
+	m.CreatedAt = m.CreatedAt.WithOwner(alias)
+	m.UpdatedAt = m.UpdatedAt.WithOwner(alias)
+	m.FeatureName = m.FeatureName.WithOwner(alias)
+	m.Type = m.Type.WithOwner(alias)
+	m.IsActive = m.IsActive.WithOwner(alias)
+	m.GormElement = m.GormElement.WithOwner(alias)
+	m.PriceUnit = m.PriceUnit.WithOwner(alias)
+	return m
+}
+
 // GormTest_ contains field name constants for GormTest
-var GormTest_ = struct {
-	TableName      string
-	Id             Field
-	Uuid           Field
-	CreatedAt      Field
-	UpdatedAt      Field
-	FeatureName    Field
-	Type           Field
-	IsActive       Field
-	GormElement    Field
-	PriceUnit      Field
-	EmbeddedEntity Field
-}{
-	TableName:      "gorm_tests",
-	Id:             Field{FieldName: "id", TableName: "gorm_tests"},
-	Uuid:           Field{FieldName: "uuid", TableName: "gorm_tests"},
-	CreatedAt:      Field{FieldName: "created_at", TableName: "gorm_tests"},
-	UpdatedAt:      Field{FieldName: "updated_at", TableName: "gorm_tests"},
-	FeatureName:    Field{FieldName: "feature_name", TableName: "gorm_tests"},
-	Type:           Field{FieldName: "type", TableName: "gorm_tests"},
-	IsActive:       Field{FieldName: "is_active", TableName: "gorm_tests"},
-	GormElement:    Field{FieldName: "gorm_element", TableName: "gorm_tests"},
-	PriceUnit:      Field{FieldName: "price_unit", TableName: "gorm_tests"},
-	EmbeddedEntity: Field{FieldName: "embedded_entity", TableName: "gorm_tests"},
+var GormTest_ = GormTestMetamodel{
+	TableName:   "gorm_tests",
+	Id:          NumberField[uint]{Field: Field[uint]{FieldName: "id", TableName: "gorm_tests"}},
+	Uuid:        Field[uuid.UUID]{FieldName: "uuid", TableName: "gorm_tests"},
+	CreatedAt:   TimeField[time.Time]{Field: Field[time.Time]{FieldName: "created_at", TableName: "gorm_tests"}},
+	UpdatedAt:   TimeField[time.Time]{Field: Field[time.Time]{FieldName: "updated_at", TableName: "gorm_tests"}},
+	FeatureName: StringField[string]{Field: Field[string]{FieldName: "feature_name", TableName: "gorm_tests"}},
+	Type:        NumberField[int]{Field: Field[int]{FieldName: "type", TableName: "gorm_tests"}},
+	IsActive:    BoolField[bool]{Field: Field[bool]{FieldName: "is_active", TableName: "gorm_tests"}},
+	GormElement: SliceField[any]{Field: Field[any]{FieldName: "gorm_element", TableName: "gorm_tests"}},
+	PriceUnit:   StringField[string]{Field: Field[string]{FieldName: "price_unit", TableName: "gorm_tests"}},
 }
 
-// GormElement_ contains field name constants for GormElement
-var GormElement_ = struct {
+// GormElementMetamodel is the type of GormElement_.
+type GormElementMetamodel struct {
 	TableName string
-	Name      Field
-}{
-	TableName: "gorm_tests",
-	Name:      Field{FieldName: "name", TableName: "gorm_tests"},
+	Name      StringField[string]
 }
 
-// EmbeddedEntity_ contains field name constants for EmbeddedEntity
-var EmbeddedEntity_ = struct {
+// As returns a copy of GormElement_ whose fields are qualified by alias, for
+// self-joins and queries that reference the table more than once.
+// Example: GormElement_.As("t")
+func (m GormElementMetamodel) As(alias string) GormElementMetamodel {
+	m.Name = m.Name.WithOwner(alias)
+	return m
+}
+
+// GormElement_ contains field name constants for GormElement
+var GormElement_ = GormElementMetamodel{
+	TableName: "gorm_elements",
+	Name:      StringField[string]{Field: Field[string]{FieldName: "name", TableName: "gorm_elements"}},
+}
+
+// EmbeddedEntityMetamodel is the type of EmbeddedEntity_.
+type EmbeddedEntityMetamodel struct {
 	TableName    string
-	Id           Field
-	Uuid         Field
-	CreatedAt    Field
-	UpdatedAt    Field
-	CategoryType Field
-	ParentId     Field
-	Value        Field
-}{
-	TableName:    "gorm_tests",
-	Id:           Field{FieldName: "id", TableName: "gorm_tests"},
-	Uuid:         Field{FieldName: "uuid", TableName: "gorm_tests"},
-	CreatedAt:    Field{FieldName: "created_at", TableName: "gorm_tests"},
-	UpdatedAt:    Field{FieldName: "updated_at", TableName: "gorm_tests"},
-	CategoryType: Field{FieldName: "category_type", TableName: "gorm_tests"},
-	ParentId:     Field{FieldName: "parent_id", TableName: "gorm_tests"},
-	Value:        Field{FieldName: "value", TableName: "gorm_tests"},
+	Id           NumberField[uint]
+	Uuid         Field[uuid.UUID]
+	CreatedAt    TimeField[time.Time]
+	UpdatedAt    TimeField[time.Time]
+	CategoryType StringField[string]
+	ParentId     NumberField[uint32]
+	Value        NumberField[uint32]
+}
+
+// As returns a copy of EmbeddedEntity_ whose fields are qualified by alias, for
+// self-joins and queries that reference the table more than once.
+// Example: EmbeddedEntity_.As("t")
+func (m EmbeddedEntityMetamodel) As(alias string) EmbeddedEntityMetamodel {
+	m.Id = m.Id.WithOwner(alias)
+	m.Uuid = m.Uuid.WithOwner(alias)
+	m.CreatedAt = m.CreatedAt.WithOwner(alias)
+	m.UpdatedAt = m.UpdatedAt.WithOwner(alias)
+	m.CategoryType = m.CategoryType.WithOwner(alias)
+	m.ParentId = m.ParentId.WithOwner(alias)
+	m.Value = m.Value.WithOwner(alias)
+	return m
+}
+
+// EmbeddedEntity_ contains field name constants for EmbeddedEntity
+var EmbeddedEntity_ = EmbeddedEntityMetamodel{
+	TableName:    "embedded_entity",
+	Id:           NumberField[uint]{Field: Field[uint]{FieldName: "id", TableName: "embedded_entity"}},
+	Uuid:         Field[uuid.UUID]{FieldName: "uuid", TableName: "embedded_entity"},
+	CreatedAt:    TimeField[time.Time]{Field: Field[time.Time]{FieldName: "created_at", TableName: "embedded_entity"}},
+	UpdatedAt:    TimeField[time.Time]{Field: Field[time.Time]{FieldName: "updated_at", TableName: "embedded_entity"}},
+	CategoryType: StringField[string]{Field: Field[string]{FieldName: "category_type", TableName: "embedded_entity"}},
+	ParentId:     NumberField[uint32]{Field: Field[uint32]{FieldName: "parent_id", TableName: "embedded_entity"}},
+	Value:        NumberField[uint32]{Field: Field[uint32]{FieldName: "value", TableName: "embedded_entity"}},
 }
@@ -9,139 +9,517 @@ import (
 	"gorm.io/gorm/clause"
 )
 
-// Field represents a database column with its name and table name.
-type Field struct {
+// Field represents a database column with its name and table name. T is the
+// Go type of the struct field, so operands of the wrong type do not compile;
+// it is any for types the metamodel package cannot name.
+// Columns of a known Go type are generated as StringField, BoolField,
+// NumberField, TimeField or SliceField, adding the operators of that type.
+// JSON, BSON and Column hold the field's name under each struct tag when the
+// metamodel is generated from several tags (-tag=json,bson,gorm).
+// Alias, when set, is the table alias the column is qualified with in SQL.
+type Field[T any] struct {
 	FieldName string
 	TableName string
+	Alias     string
+	JSON      string
+	BSON      string
+	Column    string
 }
 
 // Equal generates a GORM equality condition: "column = ?"
-func (f Field) Equal(val any) clause.Eq {
-	return clause.Eq{Column: f.FieldName, Value: val}
+func (f Field[T]) Equal(val T) clause.Eq {
+	return clause.Eq{Column: f, Value: val}
 }
 
-func (f Field) EqualString(val any) string {
-	return fmt.Sprintf(" %s = %v ", f.FieldName, val)
+// EqualString is the raw SQL form of Equal. Unsafe with untrusted input:
+// values are interpolated without quoting or escaping.
+func (f Field[T]) EqualString(val T) string {
+	return fmt.Sprintf(" %s = %v ", f.quoted(), val)
 }
 
 // NotEqual generates a GORM not-equal condition: "column <> ?"
-func (f Field) NotEqual(val any) clause.Neq {
-	return clause.Neq{Column: f.FieldName, Value: val}
+func (f Field[T]) NotEqual(val T) clause.Neq {
+	return clause.Neq{Column: f, Value: val}
 }
 
-func (f Field) NotEqualString(val any) string {
-	return fmt.Sprintf(" %s <> %v ", f.FieldName, val)
+// NotEqualString is the raw SQL form of NotEqual. Unsafe with untrusted input:
+// values are interpolated without quoting or escaping.
+func (f Field[T]) NotEqualString(val T) string {
+	return fmt.Sprintf(" %s <> %v ", f.quoted(), val)
 }
 
 // In generates a GORM IN condition: "column IN (?)"
-func (f Field) In(vals ...any) clause.IN {
-	return clause.IN{Column: clause.Column{Name: f.FieldName}, Values: vals}
+func (f Field[T]) In(vals ...T) clause.IN {
+	values := make([]any, len(vals))
+	for i, v := range vals {
+		values[i] = v
+	}
+	return clause.IN{Column: f, Values: values}
+}
+
+// InQuery generates a GORM condition on the rows of a subquery:
+// "column IN (SELECT ...)"
+// Example: GormTest_.Id.InQuery(NewQueryBuilder(EmbeddedEntity_.TableName).Select(EmbeddedEntity_.ParentId))
+func (f Field[T]) InQuery(qb *QueryBuilder) clause.Expr {
+	return clause.Expr{SQL: "? IN ?", Vars: []any{f, Subquery(qb)}}
 }
 
-func (f Field) InString(vals ...any) string {
+// InString is the raw SQL form of In. Unsafe with untrusted input:
+// values are interpolated without quoting or escaping.
+func (f Field[T]) InString(vals ...T) string {
 	var valStrs []string
 	for _, v := range vals {
-		valStrs = append(valStrs, fmt.Sprintf("%s", v))
+		valStrs = append(valStrs, fmt.Sprintf("%v", v))
+	}
+	return fmt.Sprintf(" %s IN (%s) ", f.quoted(), strings.Join(valStrs, ", "))
+}
+
+// Asc returns an ORDER BY ascending expression. Its column is qualified only
+// by the alias set with WithOwner or WithDefaultOwner, which a QueryBuilder
+// keeps in joined queries.
+func (f Field[T]) Asc() clause.OrderByColumn {
+	return clause.OrderByColumn{Column: clause.Column{Table: f.Alias, Name: f.column()}}
+}
+
+func (f Field[T]) AscString() string {
+	return fmt.Sprintf(" %s ASC ", f.quoted())
+}
+
+// Desc returns an ORDER BY descending expression, qualified like Asc.
+func (f Field[T]) Desc() clause.OrderByColumn {
+	return clause.OrderByColumn{Column: clause.Column{Table: f.Alias, Name: f.column()}, Desc: true}
+}
+
+func (f Field[T]) DescString() string {
+	return fmt.Sprintf(" %s DESC ", f.quoted())
+}
+
+// Build writes the column, making a Field usable as a gorm clause.Expression,
+// e.g. in QueryBuilder.Select or GroupBy. Conditions reference the Field
+// itself as their column, so a QueryBuilder with joins can qualify it with
+// its table; gorm writes the column name returned by String.
+func (f Field[T]) Build(builder clause.Builder) {
+	column := clause.Column{Table: f.Alias, Name: f.column()}
+	if q, ok := builder.(columnQualifier); ok && f.Alias == "" {
+		column.Table = q.qualifier(f.TableName)
+	}
+	builder.WriteQuoted(column)
+}
+
+// String returns the raw column name, qualified by the alias if any.
+func (f Field[T]) String() string {
+	if f.Alias != "" {
+		return f.Alias + "." + f.column()
+	}
+	return f.column()
+}
+
+// column is the name of the field's SQL column: its gorm name when the
+// metamodel was generated from several tags, its FieldName otherwise.
+func (f Field[T]) column() string {
+	if f.Column != "" {
+		return f.Column
+	}
+	return f.FieldName
+}
+
+func (f Field[T]) As(val any) string {
+	return fmt.Sprintf(" %s as %v ", f.quoted(), val)
+}
+
+// quoted returns the column name quoted for DefaultDialect, as used by the
+// *String helpers.
+func (f Field[T]) quoted() string {
+	return DefaultDialect.QuoteIdentifier(f.String())
+}
+
+// WithOwner returns the field qualified by the table or alias val, replacing
+// any earlier owner.
+// Example: GormTest_.Id.WithOwner("g").String() → "g.id"
+func (f Field[T]) WithOwner(val string) Field[T] {
+	f.Alias = strings.TrimSpace(val)
+	return f
+}
+
+// WithOwnerString returns the column qualified by the table or alias val,
+// replacing any earlier owner like WithOwner.
+func (f Field[T]) WithOwnerString(val string) string {
+	return fmt.Sprintf(" %s ", f.WithOwner(val).quoted())
+}
+
+func (f Field[T]) WithDefaultOwnerString() string {
+	return fmt.Sprintf(" %s ", f.WithDefaultOwner().quoted())
+}
+
+// WithDefaultOwner returns the field qualified by its own table.
+func (f Field[T]) WithDefaultOwner() Field[T] {
+	f.Alias = f.TableName
+	return f
+}
+
+// FieldRef is implemented by every generated field, whatever its Go type, so
+// that fields can be compared with each other.
+type FieldRef interface {
+	clause.Expression
+	columnRef() columnRef
+}
+
+func (f Field[T]) columnRef() columnRef {
+	return columnRef{table: f.TableName, alias: f.Alias, name: f.column()}
+}
+
+// columnRef is a column always written qualified: by the field's alias, or by
+// its table or the alias the table has in a QueryBuilder.
+type columnRef struct {
+	table string
+	alias string
+	name  string
+}
+
+func (c columnRef) owner(builder clause.Builder) string {
+	if c.alias != "" {
+		return c.alias
+	}
+	if q, ok := builder.(columnQualifier); ok {
+		return q.alias(c.table)
+	}
+	return c.table
+}
+
+func (c columnRef) Build(builder clause.Builder) {
+	builder.WriteQuoted(clause.Column{Table: c.owner(builder), Name: c.name})
+}
+
+func (c columnRef) String() string {
+	if owner := c.owner(nil); owner != "" {
+		return DefaultDialect.QuoteIdentifier(owner) + "." + DefaultDialect.QuoteIdentifier(c.name)
 	}
-	return fmt.Sprintf(" %s IN (%s) ", f.FieldName, strings.Join(valStrs, ", "))
+	return DefaultDialect.QuoteIdentifier(c.name)
+}
+
+// compareFields generates the condition "left op right" between two columns.
+func compareFields(left FieldRef, op string, right FieldRef) clause.Expr {
+	return clause.Expr{SQL: "? " + op + " ?", Vars: []any{left.columnRef(), right.columnRef()}}
+}
+
+func compareFieldsString(left FieldRef, op string, right FieldRef) string {
+	return fmt.Sprintf(" %s %s %s ", left.columnRef(), op, right.columnRef())
+}
+
+// EqField generates a GORM condition comparing two columns: "table.column = other_table.column"
+// Example: GormTest_.Id.EqField(EmbeddedEntity_.ParentId)
+func (f Field[T]) EqField(other FieldRef) clause.Expr {
+	return compareFields(f, "=", other)
+}
+
+func (f Field[T]) EqFieldString(other FieldRef) string {
+	return compareFieldsString(f, "=", other)
+}
+
+// NeField generates a GORM condition comparing two columns: "table.column <> other_table.column"
+func (f Field[T]) NeField(other FieldRef) clause.Expr {
+	return compareFields(f, "<>", other)
+}
+
+func (f Field[T]) NeFieldString(other FieldRef) string {
+	return compareFieldsString(f, "<>", other)
+}
+
+// StringField is a column holding text.
+type StringField[T any] struct {
+	Field[T]
+}
+
+func (f StringField[T]) WithOwner(val string) StringField[T] {
+	f.Field = f.Field.WithOwner(val)
+	return f
+}
+
+func (f StringField[T]) WithDefaultOwner() StringField[T] {
+	f.Field = f.Field.WithDefaultOwner()
+	return f
+}
+
+// Like generates a GORM pattern condition: "column LIKE ?"
+// Example: User_.Name.Like("nam%")
+func (f StringField[T]) Like(pattern string) clause.Like {
+	return clause.Like{Column: f.Field, Value: pattern}
+}
+
+// LikeString is the raw SQL form of Like. Unsafe with untrusted input:
+// values are interpolated without quoting or escaping.
+func (f StringField[T]) LikeString(pattern string) string {
+	return fmt.Sprintf(" %s LIKE '%s' ", f.quoted(), pattern)
+}
+
+// NotLike generates a GORM negated pattern condition: "column NOT LIKE ?"
+func (f StringField[T]) NotLike(pattern string) clause.Expression {
+	return clause.Not(clause.Like{Column: f.Field, Value: pattern})
+}
+
+// NotLikeString is the raw SQL form of NotLike. Unsafe with untrusted input:
+// values are interpolated without quoting or escaping.
+func (f StringField[T]) NotLikeString(pattern string) string {
+	return fmt.Sprintf(" %s NOT LIKE '%s' ", f.quoted(), pattern)
+}
+
+// BoolField is a boolean column.
+type BoolField[T any] struct {
+	Field[T]
+}
+
+func (f BoolField[T]) WithOwner(val string) BoolField[T] {
+	f.Field = f.Field.WithOwner(val)
+	return f
+}
+
+func (f BoolField[T]) WithDefaultOwner() BoolField[T] {
+	f.Field = f.Field.WithDefaultOwner()
+	return f
+}
+
+// IsTrue generates a GORM equality condition for boolean true.
+func (f BoolField[T]) IsTrue() clause.Eq {
+	return clause.Eq{Column: f.Field, Value: true}
+}
+
+func (f BoolField[T]) IsTrueString() string {
+	return fmt.Sprintf(" %s = %s ", f.quoted(), DefaultDialect.BoolLiteral(true))
+}
+
+// IsFalse generates a GORM equality condition for boolean false.
+func (f BoolField[T]) IsFalse() clause.Eq {
+	return clause.Eq{Column: f.Field, Value: false}
+}
+
+func (f BoolField[T]) IsFalseString() string {
+	return fmt.Sprintf(" %s = %s ", f.quoted(), DefaultDialect.BoolLiteral(false))
+}
+
+// NumberField is a numeric column, supporting ordering and ranges.
+type NumberField[T any] struct {
+	Field[T]
+}
+
+func (f NumberField[T]) WithOwner(val string) NumberField[T] {
+	f.Field = f.Field.WithOwner(val)
+	return f
+}
+
+func (f NumberField[T]) WithDefaultOwner() NumberField[T] {
+	f.Field = f.Field.WithDefaultOwner()
+	return f
 }
 
 // Gt generates a GORM greater-than condition: "column > ?"
-func (f Field) Gt(val any) clause.Gt {
-	return clause.Gt{Column: f.FieldName, Value: val}
+func (f NumberField[T]) Gt(val T) clause.Gt {
+	return clause.Gt{Column: f.Field, Value: val}
 }
 
-func (f Field) GtString(val any) string {
-	return fmt.Sprintf(" %s > %v ", f.FieldName, val)
+// GtString is the raw SQL form of Gt. Unsafe with untrusted input:
+// values are interpolated without quoting or escaping.
+func (f NumberField[T]) GtString(val T) string {
+	return fmt.Sprintf(" %s > %v ", f.quoted(), val)
 }
 
 // Gte generates a GORM greater-than-or-equal condition: "column >= ?"
-func (f Field) Gte(val any) clause.Gte {
-	return clause.Gte{Column: f.FieldName, Value: val}
+func (f NumberField[T]) Gte(val T) clause.Gte {
+	return clause.Gte{Column: f.Field, Value: val}
 }
 
-func (f Field) GteString(val any) string {
-	return fmt.Sprintf(" %s >= %v ", f.FieldName, val)
+// GteString is the raw SQL form of Gte. Unsafe with untrusted input:
+// values are interpolated without quoting or escaping.
+func (f NumberField[T]) GteString(val T) string {
+	return fmt.Sprintf(" %s >= %v ", f.quoted(), val)
 }
 
 // Lt generates a GORM less-than condition: "column < ?"
-func (f Field) Lt(val any) clause.Lt {
-	return clause.Lt{Column: f.FieldName, Value: val}
+func (f NumberField[T]) Lt(val T) clause.Lt {
+	return clause.Lt{Column: f.Field, Value: val}
 }
 
-func (f Field) LtString(val any) string {
-	return fmt.Sprintf(" %s < %v ", f.FieldName, val)
+// LtString is the raw SQL form of Lt. Unsafe with untrusted input:
+// values are interpolated without quoting or escaping.
+func (f NumberField[T]) LtString(val T) string {
+	return fmt.Sprintf(" %s < %v ", f.quoted(), val)
 }
 
 // Lte generates a GORM less-than-or-equal condition: "column <= ?"
-func (f Field) Lte(val any) clause.Lte {
-	return clause.Lte{Column: f.FieldName, Value: val}
+func (f NumberField[T]) Lte(val T) clause.Lte {
+	return clause.Lte{Column: f.Field, Value: val}
 }
 
-func (f Field) LteString(val any) string {
-	return fmt.Sprintf(" %s <= %v ", f.FieldName, val)
+// LteString is the raw SQL form of Lte. Unsafe with untrusted input:
+// values are interpolated without quoting or escaping.
+func (f NumberField[T]) LteString(val T) string {
+	return fmt.Sprintf(" %s <= %v ", f.quoted(), val)
 }
 
-// IsTrue generates a GORM equality condition for boolean true.
-func (f Field) IsTrue() clause.Eq {
-	return clause.Eq{Column: f.FieldName, Value: true}
+// Between generates a GORM range condition: "column BETWEEN ? AND ?"
+func (f NumberField[T]) Between(from, to T) clause.Expr {
+	return clause.Expr{SQL: "? BETWEEN ? AND ?", Vars: []any{f.Field, from, to}}
 }
 
-func (f Field) IsTrueString() string {
-	return fmt.Sprintf(" %s = true ", f.FieldName)
+// BetweenString is the raw SQL form of Between. Unsafe with untrusted input:
+// values are interpolated without quoting or escaping.
+func (f NumberField[T]) BetweenString(from, to T) string {
+	return fmt.Sprintf(" %s BETWEEN %v AND %v ", f.quoted(), from, to)
 }
 
-// IsFalse generates a GORM equality condition for boolean false.
-func (f Field) IsFalse() clause.Eq {
-	return clause.Eq{Column: f.FieldName, Value: false}
+// GtField generates a GORM greater-than condition between two columns: "table.column > other_table.column"
+func (f NumberField[T]) GtField(other FieldRef) clause.Expr {
+	return compareFields(f, ">", other)
 }
 
-func (f Field) IsFalseString() string {
-	return fmt.Sprintf(" %s = false ", f.FieldName)
+func (f NumberField[T]) GtFieldString(other FieldRef) string {
+	return compareFieldsString(f, ">", other)
 }
 
-// Asc returns an ORDER BY ascending expression.
-func (f Field) Asc() clause.OrderByColumn {
-	return clause.OrderByColumn{Column: clause.Column{Name: f.FieldName}}
+// GteField generates a GORM greater-than-or-equal condition between two columns: "table.column >= other_table.column"
+func (f NumberField[T]) GteField(other FieldRef) clause.Expr {
+	return compareFields(f, ">=", other)
 }
 
-func (f Field) AscString() string {
-	return fmt.Sprintf(" %s ASC ", f.FieldName)
+func (f NumberField[T]) GteFieldString(other FieldRef) string {
+	return compareFieldsString(f, ">=", other)
 }
 
-// Desc returns an ORDER BY descending expression.
-func (f Field) Desc() clause.OrderByColumn {
-	return clause.OrderByColumn{Column: clause.Column{Name: f.FieldName}, Desc: true}
+// LtField generates a GORM less-than condition between two columns: "table.column < other_table.column"
+func (f NumberField[T]) LtField(other FieldRef) clause.Expr {
+	return compareFields(f, "<", other)
 }
 
-func (f Field) DescString() string {
-	return fmt.Sprintf(" %s DESC ", f.FieldName)
+func (f NumberField[T]) LtFieldString(other FieldRef) string {
+	return compareFieldsString(f, "<", other)
 }
 
-// String returns the raw column name.
-func (f Field) String() string {
-	return f.FieldName
+// LteField generates a GORM less-than-or-equal condition between two columns: "table.column <= other_table.column"
+func (f NumberField[T]) LteField(other FieldRef) clause.Expr {
+	return compareFields(f, "<=", other)
 }
 
-func (f Field) As(val any) string {
-	return fmt.Sprintf(" %s as %v ", f.FieldName, val)
+func (f NumberField[T]) LteFieldString(other FieldRef) string {
+	return compareFieldsString(f, "<=", other)
 }
 
-func (f Field) WithOwner(val string) Field {
-	f.FieldName = fmt.Sprintf(" %v.%s ", val, f.FieldName)
+// TimeField is a date or time column, supporting ordering and ranges.
+type TimeField[T any] struct {
+	Field[T]
+}
+
+func (f TimeField[T]) WithOwner(val string) TimeField[T] {
+	f.Field = f.Field.WithOwner(val)
 	return f
 }
 
-func (f Field) WithOwnerString(val string) string {
-	return fmt.Sprintf(" %s.%s ", val, f.FieldName)
+func (f TimeField[T]) WithDefaultOwner() TimeField[T] {
+	f.Field = f.Field.WithDefaultOwner()
+	return f
+}
+
+// Gt generates a GORM greater-than condition: "column > ?"
+func (f TimeField[T]) Gt(val T) clause.Gt {
+	return clause.Gt{Column: f.Field, Value: val}
+}
+
+// GtString is the raw SQL form of Gt. Unsafe with untrusted input:
+// values are interpolated without quoting or escaping.
+func (f TimeField[T]) GtString(val T) string {
+	return fmt.Sprintf(" %s > %v ", f.quoted(), val)
+}
+
+// Gte generates a GORM greater-than-or-equal condition: "column >= ?"
+func (f TimeField[T]) Gte(val T) clause.Gte {
+	return clause.Gte{Column: f.Field, Value: val}
+}
+
+// GteString is the raw SQL form of Gte. Unsafe with untrusted input:
+// values are interpolated without quoting or escaping.
+func (f TimeField[T]) GteString(val T) string {
+	return fmt.Sprintf(" %s >= %v ", f.quoted(), val)
+}
+
+// Lt generates a GORM less-than condition: "column < ?"
+func (f TimeField[T]) Lt(val T) clause.Lt {
+	return clause.Lt{Column: f.Field, Value: val}
+}
+
+// LtString is the raw SQL form of Lt. Unsafe with untrusted input:
+// values are interpolated without quoting or escaping.
+func (f TimeField[T]) LtString(val T) string {
+	return fmt.Sprintf(" %s < %v ", f.quoted(), val)
+}
+
+// Lte generates a GORM less-than-or-equal condition: "column <= ?"
+func (f TimeField[T]) Lte(val T) clause.Lte {
+	return clause.Lte{Column: f.Field, Value: val}
+}
+
+// LteString is the raw SQL form of Lte. Unsafe with untrusted input:
+// values are interpolated without quoting or escaping.
+func (f TimeField[T]) LteString(val T) string {
+	return fmt.Sprintf(" %s <= %v ", f.quoted(), val)
+}
+
+// Between generates a GORM range condition: "column BETWEEN ? AND ?"
+func (f TimeField[T]) Between(from, to T) clause.Expr {
+	return clause.Expr{SQL: "? BETWEEN ? AND ?", Vars: []any{f.Field, from, to}}
+}
+
+// BetweenString is the raw SQL form of Between. Unsafe with untrusted input:
+// values are interpolated without quoting or escaping.
+func (f TimeField[T]) BetweenString(from, to T) string {
+	return fmt.Sprintf(" %s BETWEEN %v AND %v ", f.quoted(), from, to)
+}
+
+// GtField generates a GORM greater-than condition between two columns: "table.column > other_table.column"
+func (f TimeField[T]) GtField(other FieldRef) clause.Expr {
+	return compareFields(f, ">", other)
+}
+
+func (f TimeField[T]) GtFieldString(other FieldRef) string {
+	return compareFieldsString(f, ">", other)
 }
 
-func (f Field) WithDefaultOwnerString() string {
-	return fmt.Sprintf(" %s.%s ", f.TableName, f.FieldName)
+// GteField generates a GORM greater-than-or-equal condition between two columns: "table.column >= other_table.column"
+func (f TimeField[T]) GteField(other FieldRef) clause.Expr {
+	return compareFields(f, ">=", other)
 }
 
-func (f Field) WithDefaultOwner() Field {
-	f.FieldName = fmt.Sprintf(" %s.%s ", f.TableName, f.FieldName)
+func (f TimeField[T]) GteFieldString(other FieldRef) string {
+	return compareFieldsString(f, ">=", other)
+}
+
+// LtField generates a GORM less-than condition between two columns: "table.column < other_table.column"
+func (f TimeField[T]) LtField(other FieldRef) clause.Expr {
+	return compareFields(f, "<", other)
+}
+
+func (f TimeField[T]) LtFieldString(other FieldRef) string {
+	return compareFieldsString(f, "<", other)
+}
+
+// LteField generates a GORM less-than-or-equal condition between two columns: "table.column <= other_table.column"
+func (f TimeField[T]) LteField(other FieldRef) clause.Expr {
+	return compareFields(f, "<=", other)
+}
+
+func (f TimeField[T]) LteFieldString(other FieldRef) string {
+	return compareFieldsString(f, "<=", other)
+}
+
+// SliceField is a column holding a list, such as a Postgres array or a serialized slice.
+type SliceField[T any] struct {
+	Field[T]
+}
+
+func (f SliceField[T]) WithOwner(val string) SliceField[T] {
+	f.Field = f.Field.WithOwner(val)
+	return f
+}
+
+func (f SliceField[T]) WithDefaultOwner() SliceField[T] {
+	f.Field = f.Field.WithDefaultOwner()
 	return f
 }
 
@@ -153,15 +531,14 @@ func (f Field) WithDefaultOwner() Field {
 //	    CategoryEntity_.Value.As("category_id"),
 //	)).Where(...).GroupBy(...).Having(...).Order(...)
 //
-// Example full query:
+// Aggregates are expressions rather than strings; select them with
+// placeholders (see Expr):
 //
-//	db.Select(Columns(
-//	    GormTest_.FeatureName.String(),
-//	    "COUNT(*) as total",
-//	)).Where(GormTest_.Status.Equal("active")).
-//	  GroupBy(GormTest_.FeatureName.String()).
-//	  Having(clause.Gt{Column: "COUNT(*)", Value: 5}).
-//	  Order(GormTest_.CreatedAt.Desc()).
+//	db.Select("?, ?", GormTest_.FeatureName, CountAll().As("total")).
+//	  Where(GormTest_.IsActive.IsTrue()).
+//	  Group(GormTest_.FeatureName.String()).
+//	  Having(CountAll().Gt(5)).
+//	  Order(CountAll().Desc()).
 //	  Find(&results)
 func Columns(cols ...string) string {
 	return strings.Join(cols, ", ")
@@ -190,94 +567,3 @@ func Or(conditions ...clause.Expression) clause.OrConditions {
 func OrString(conditions ...string) string {
 	return strings.Join(conditions, " OR ")
 }
-
-// QueryBuilder helps construct complete SQL SELECT statements.
-type QueryBuilder struct {
-	selectCols  []string
-	fromTable   string
-	whereConds  []string
-	groupByCols []string
-	havingConds []string
-	orderByCols []string
-}
-
-// NewQueryBuilder creates a new QueryBuilder for the given table.
-func NewQueryBuilder(tableName string) *QueryBuilder {
-	return &QueryBuilder{
-		fromTable: tableName,
-	}
-}
-
-// Select adds columns to the SELECT clause.
-func (qb *QueryBuilder) Select(cols ...string) *QueryBuilder {
-	qb.selectCols = append(qb.selectCols, cols...)
-	return qb
-}
-
-// Where adds conditions to the WHERE clause.
-func (qb *QueryBuilder) Where(conditions ...string) *QueryBuilder {
-	qb.whereConds = append(qb.whereConds, conditions...)
-	return qb
-}
-
-// GroupBy adds columns to the GROUP BY clause.
-func (qb *QueryBuilder) GroupBy(cols ...string) *QueryBuilder {
-	qb.groupByCols = append(qb.groupByCols, cols...)
-	return qb
-}
-
-// Having adds conditions to the HAVING clause.
-func (qb *QueryBuilder) Having(conditions ...string) *QueryBuilder {
-	qb.havingConds = append(qb.havingConds, conditions...)
-	return qb
-}
-
-// OrderBy adds columns to the ORDER BY clause.
-func (qb *QueryBuilder) OrderBy(cols ...string) *QueryBuilder {
-	qb.orderByCols = append(qb.orderByCols, cols...)
-	return qb
-}
-
-// Build constructs and returns the complete SQL SELECT statement as a string.
-// Expected format: "SELECT a,b,c FROM table WHERE ... GROUP BY ... HAVING ... ORDER BY ..."
-func (qb *QueryBuilder) Build() string {
-	var query strings.Builder
-
-	// SELECT clause
-	query.WriteString("SELECT ")
-	if len(qb.selectCols) > 0 {
-		query.WriteString(strings.Join(qb.selectCols, ","))
-	} else {
-		query.WriteString("*")
-	}
-
-	// FROM clause
-	query.WriteString(" FROM ")
-	query.WriteString(qb.fromTable)
-
-	// WHERE clause
-	if len(qb.whereConds) > 0 {
-		query.WriteString(" WHERE ")
-		query.WriteString(strings.Join(qb.whereConds, " AND "))
-	}
-
-	// GROUP BY clause
-	if len(qb.groupByCols) > 0 {
-		query.WriteString(" GROUP BY ")
-		query.WriteString(strings.Join(qb.groupByCols, ","))
-	}
-
-	// HAVING clause
-	if len(qb.havingConds) > 0 {
-		query.WriteString(" HAVING ")
-		query.WriteString(strings.Join(qb.havingConds, " AND "))
-	}
-
-	// ORDER BY clause
-	if len(qb.orderByCols) > 0 {
-		query.WriteString(" ORDER BY ")
-		query.WriteString(strings.Join(qb.orderByCols, ","))
-	}
-
-	return query.String()
-}
@@ -3,62 +3,36 @@
 package metamodel_
 
 import (
+	"strconv"
+	"strings"
+
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 // MgoEq generates a MongoDB equality filter: { field: { $eq: val } }
 // Example: User_.Name.MgoEq("test") → bson.D{{"name", bson.D{{"$eq", "test"}}}}
-func (f Field) MgoEq(val any) bson.D {
-	return bson.D{{Key: f.FieldName, Value: bson.D{{Key: "$eq", Value: val}}}}
+func (f Field[T]) MgoEq(val T) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$eq", Value: val}}}}
 }
 
 // MgoNe generates a MongoDB not-equal filter: { field: { $ne: val } }
-func (f Field) MgoNe(val any) bson.D {
-	return bson.D{{Key: f.FieldName, Value: bson.D{{Key: "$ne", Value: val}}}}
-}
-
-// MgoGt generates a MongoDB greater-than filter: { field: { $gt: val } }
-func (f Field) MgoGt(val any) bson.D {
-	return bson.D{{Key: f.FieldName, Value: bson.D{{Key: "$gt", Value: val}}}}
-}
-
-// MgoGte generates a MongoDB greater-than-or-equal filter: { field: { $gte: val } }
-func (f Field) MgoGte(val any) bson.D {
-	return bson.D{{Key: f.FieldName, Value: bson.D{{Key: "$gte", Value: val}}}}
-}
-
-// MgoLt generates a MongoDB less-than filter: { field: { $lt: val } }
-func (f Field) MgoLt(val any) bson.D {
-	return bson.D{{Key: f.FieldName, Value: bson.D{{Key: "$lt", Value: val}}}}
-}
-
-// MgoLte generates a MongoDB less-than-or-equal filter: { field: { $lte: val } }
-func (f Field) MgoLte(val any) bson.D {
-	return bson.D{{Key: f.FieldName, Value: bson.D{{Key: "$lte", Value: val}}}}
+func (f Field[T]) MgoNe(val T) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$ne", Value: val}}}}
 }
 
 // MgoIn generates a MongoDB $in filter: { field: { $in: [vals...] } }
-func (f Field) MgoIn(vals ...any) bson.D {
-	return bson.D{{Key: f.FieldName, Value: bson.D{{Key: "$in", Value: vals}}}}
+func (f Field[T]) MgoIn(vals ...T) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$in", Value: vals}}}}
 }
 
 // MgoNin generates a MongoDB $nin filter: { field: { $nin: [vals...] } }
-func (f Field) MgoNin(vals ...any) bson.D {
-	return bson.D{{Key: f.FieldName, Value: bson.D{{Key: "$nin", Value: vals}}}}
+func (f Field[T]) MgoNin(vals ...T) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$nin", Value: vals}}}}
 }
 
 // MgoExists generates a MongoDB $exists filter: { field: { $exists: exists } }
-func (f Field) MgoExists(exists bool) bson.D {
-	return bson.D{{Key: f.FieldName, Value: bson.D{{Key: "$exists", Value: exists}}}}
-}
-
-// MgoRegex generates a MongoDB $regex filter: { field: { $regex: pattern, $options: opts } }
-// Example: User_.Name.MgoRegex("^test", "i")
-func (f Field) MgoRegex(pattern string, opts string) bson.D {
-	return bson.D{{Key: f.FieldName, Value: bson.D{
-		{Key: "$regex", Value: pattern},
-		{Key: "$options", Value: opts},
-	}}}
+func (f Field[T]) MgoExists(exists bool) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$exists", Value: exists}}}}
 }
 
 // MgoAnd combines multiple MongoDB filters with $and:
@@ -95,19 +69,264 @@ func MgoNor(filters ...bson.D) bson.D {
 
 // MgoNot wraps a field condition with $not: { field: { $not: { op: val } } }
 // Example: User_.Age.MgoNot(User_.Age.MgoGt(18))
-func (f Field) MgoNot(filter bson.D) bson.D {
+func (f Field[T]) MgoNot(filter bson.D) bson.D {
 	if len(filter) == 0 {
 		return bson.D{}
 	}
-	return bson.D{{Key: f.FieldName, Value: bson.D{{Key: "$not", Value: filter[0].Value}}}}
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$not", Value: filter[0].Value}}}}
 }
 
 // MgoAsc generates a MongoDB ascending sort document: { field: 1 }
-func (f Field) MgoAsc() bson.D {
-	return bson.D{{Key: f.FieldName, Value: 1}}
+func (f Field[T]) MgoAsc() bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: 1}}
 }
 
 // MgoDesc generates a MongoDB descending sort document: { field: -1 }
-func (f Field) MgoDesc() bson.D {
-	return bson.D{{Key: f.FieldName, Value: -1}}
+func (f Field[T]) MgoDesc() bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: -1}}
+}
+
+// MgoRegex generates a MongoDB $regex filter: { field: { $regex: pattern, $options: opts } }
+// Example: User_.Name.MgoRegex("^test", "i")
+func (f StringField[T]) MgoRegex(pattern string, opts string) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{
+		{Key: "$regex", Value: pattern},
+		{Key: "$options", Value: opts},
+	}}}
+}
+
+// MgoGt generates a MongoDB greater-than filter: { field: { $gt: val } }
+// MongoDB orders values of every type, so ranges apply to strings and
+// ObjectIDs as well: Scenarios_.ID.MgoGt(lastID) pages through a collection.
+func (f Field[T]) MgoGt(val T) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$gt", Value: val}}}}
+}
+
+// MgoGte generates a MongoDB greater-than-or-equal filter: { field: { $gte: val } }
+func (f Field[T]) MgoGte(val T) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$gte", Value: val}}}}
+}
+
+// MgoLt generates a MongoDB less-than filter: { field: { $lt: val } }
+func (f Field[T]) MgoLt(val T) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$lt", Value: val}}}}
+}
+
+// MgoLte generates a MongoDB less-than-or-equal filter: { field: { $lte: val } }
+func (f Field[T]) MgoLte(val T) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$lte", Value: val}}}}
+}
+
+// mgoKey is the key of the field in MongoDB documents: its bson name when the
+// metamodel was generated from several tags, its FieldName otherwise.
+func (f Field[T]) mgoKey() string {
+	if f.BSON != "" {
+		return f.BSON
+	}
+	return f.FieldName
+}
+
+// MgoUpdate merges update documents into one, grouping the fields of each
+// update operator under a single key, as UpdateOne and UpdateMany expect:
+// { $set: { a: 1, b: 2 }, $inc: { c: 1 } }
+// Example: MgoUpdate(User_.Name.MgoSet("test"), User_.Age.MgoInc(1), User_.UpdatedAt.MgoCurrentDate())
+func MgoUpdate(updates ...bson.D) bson.D {
+	merged := bson.D{}
+	index := make(map[string]int)
+	for _, update := range updates {
+		for _, op := range update {
+			fields, ok := op.Value.(bson.D)
+			if !ok {
+				merged = append(merged, op)
+				continue
+			}
+			i, seen := index[op.Key]
+			if !seen {
+				index[op.Key] = len(merged)
+				merged = append(merged, bson.E{Key: op.Key, Value: append(bson.D{}, fields...)})
+				continue
+			}
+			if group, ok := merged[i].Value.(bson.D); ok {
+				merged[i].Value = append(group, fields...)
+			}
+		}
+	}
+	return merged
+}
+
+// mgoUpdate generates the update document { op: { field: val } }
+func mgoUpdate(op, key string, val any) bson.D {
+	return bson.D{{Key: op, Value: bson.D{{Key: key, Value: val}}}}
+}
+
+// MgoSet generates a MongoDB $set update: { $set: { field: val } }
+func (f Field[T]) MgoSet(val T) bson.D {
+	return mgoUpdate("$set", f.mgoKey(), val)
+}
+
+// MgoUnset generates a MongoDB $unset update, removing the field: { $unset: { field: "" } }
+func (f Field[T]) MgoUnset() bson.D {
+	return mgoUpdate("$unset", f.mgoKey(), "")
+}
+
+// MgoMin generates a MongoDB $min update, lowering the field to val when
+// val is smaller: { $min: { field: val } }
+func (f Field[T]) MgoMin(val T) bson.D {
+	return mgoUpdate("$min", f.mgoKey(), val)
+}
+
+// MgoMax generates a MongoDB $max update, raising the field to val when val
+// is larger: { $max: { field: val } }
+func (f Field[T]) MgoMax(val T) bson.D {
+	return mgoUpdate("$max", f.mgoKey(), val)
+}
+
+// MgoInc generates a MongoDB $inc update: { $inc: { field: delta } }
+func (f NumberField[T]) MgoInc(delta T) bson.D {
+	return mgoUpdate("$inc", f.mgoKey(), delta)
+}
+
+// MgoMul generates a MongoDB $mul update: { $mul: { field: factor } }
+func (f NumberField[T]) MgoMul(factor T) bson.D {
+	return mgoUpdate("$mul", f.mgoKey(), factor)
+}
+
+// MgoCurrentDate generates a MongoDB $currentDate update, setting the field
+// to the server's current date: { $currentDate: { field: true } }
+func (f TimeField[T]) MgoCurrentDate() bson.D {
+	return mgoUpdate("$currentDate", f.mgoKey(), true)
+}
+
+// MgoEq matches arrays holding an element equal to val, or equal to val as a
+// whole: { field: { $eq: val } }
+// Example: Scenarios_.Tags.MgoEq("urgent")
+func (f SliceField[T]) MgoEq(val any) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$eq", Value: val}}}}
+}
+
+// MgoNe matches arrays holding no element equal to val: { field: { $ne: val } }
+func (f SliceField[T]) MgoNe(val any) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$ne", Value: val}}}}
+}
+
+// MgoIn matches arrays holding one of vals: { field: { $in: [vals...] } }
+func (f SliceField[T]) MgoIn(vals ...any) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$in", Value: vals}}}}
+}
+
+// MgoNin matches arrays holding none of vals: { field: { $nin: [vals...] } }
+func (f SliceField[T]) MgoNin(vals ...any) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$nin", Value: vals}}}}
+}
+
+// MgoPush generates a MongoDB $push update appending vals to the array:
+// { $push: { field: val } }, or { $push: { field: { $each: [vals...] } } }
+// for several values.
+func (f SliceField[T]) MgoPush(vals ...any) bson.D {
+	return mgoUpdate("$push", f.mgoKey(), mgoEach(vals))
+}
+
+// MgoAddToSet generates a MongoDB $addToSet update appending the vals the
+// array does not hold yet: { $addToSet: { field: val } }, or with $each.
+func (f SliceField[T]) MgoAddToSet(vals ...any) bson.D {
+	return mgoUpdate("$addToSet", f.mgoKey(), mgoEach(vals))
+}
+
+// MgoPull generates a MongoDB $pull update removing the elements equal to
+// val or matching the condition val: { $pull: { field: val } }
+// Example: User_.Tags.MgoPull("old") or User_.Scores.MgoPull(bson.D{{"$lt", 10}})
+func (f SliceField[T]) MgoPull(val any) bson.D {
+	return mgoUpdate("$pull", f.mgoKey(), val)
+}
+
+// mgoEach returns the single value, or the values under $each.
+func mgoEach(vals []any) any {
+	if len(vals) == 1 {
+		return vals[0]
+	}
+	return bson.D{{Key: "$each", Value: vals}}
+}
+
+// MgoAll matches arrays holding every one of vals: { field: { $all: [vals...] } }
+func (f SliceField[T]) MgoAll(vals ...any) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$all", Value: vals}}}}
+}
+
+// MgoSize matches arrays of exactly n elements: { field: { $size: n } }
+func (f SliceField[T]) MgoSize(n int) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$size", Value: n}}}}
+}
+
+// MgoElemMatch matches arrays holding an element that satisfies all filters:
+// { field: { $elemMatch: { ... } } }. Filters on the fields of an array of
+// documents are made relative to the element:
+// Scenarios_.Items.MgoElemMatch(Scenarios_.Items.Name.MgoEq("a"), Scenarios_.Items.Qty.MgoGt(1))
+// → { items: { $elemMatch: { name: { $eq: "a" }, qty: { $gt: 1 } } } }
+// Operators apply to the elements of arrays of values:
+// Scenarios_.Scores.MgoElemMatch(bson.D{{"$gte", 80}}, bson.D{{"$lt", 85}})
+func (f SliceField[T]) MgoElemMatch(filters ...bson.D) bson.D {
+	prefix := f.mgoKey() + "."
+	cond := bson.D{}
+	for _, filter := range filters {
+		for _, e := range filter {
+			e.Key = strings.TrimPrefix(e.Key, prefix)
+			cond = append(cond, e)
+		}
+	}
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$elemMatch", Value: cond}}}}
+}
+
+// MgoType matches values of the given BSON types, such as "string", "int" or
+// "array": { field: { $type: type } }, or { $type: [types...] } for several.
+func (f Field[T]) MgoType(types ...string) bson.D {
+	var val any = types
+	if len(types) == 1 {
+		val = types[0]
+	}
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$type", Value: val}}}}
+}
+
+// MgoMod matches values whose remainder when divided by divisor is remainder:
+// { field: { $mod: [divisor, remainder] } }
+func (f NumberField[T]) MgoMod(divisor, remainder T) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$mod", Value: bson.A{divisor, remainder}}}}}
+}
+
+// At returns element i of the array field: "field.i"
+// Example: Scenarios_.Tags.At(0).MgoEq("first")
+func (f SliceField[T]) At(i int) Field[any] {
+	return f.element(strconv.Itoa(i))
+}
+
+// Positional returns the element of the array field matched by the filter of
+// an update: "field.$"
+// Example: coll.UpdateOne(ctx, Scenarios_.Tags.MgoEq("old"), Scenarios_.Tags.Positional().MgoSet("new"))
+func (f SliceField[T]) Positional() Field[any] {
+	return f.element("$")
+}
+
+// AllPositional returns every element of the array field, for updates: "field.$[]"
+func (f SliceField[T]) AllPositional() Field[any] {
+	return f.element("$[]")
+}
+
+// element returns the field path of an element of the array field.
+func (f Field[T]) element(index string) Field[any] {
+	e := Field[any](f)
+	e.FieldName += "." + index
+	if e.BSON != "" {
+		e.BSON += "." + index
+	}
+	return e
+}
+
+// rebase moves a field of an array element from under the path of the array,
+// from, to the path of an element, to: "items.name" becomes "items.$.name".
+func (f *Field[T]) rebase(from, to Field[any]) {
+	if rest, ok := strings.CutPrefix(f.FieldName, from.FieldName); ok {
+		f.FieldName = to.FieldName + rest
+	}
+	if rest, ok := strings.CutPrefix(f.BSON, from.BSON); ok && f.BSON != "" {
+		f.BSON = to.BSON + rest
+	}
 }
@@ -3,22 +3,133 @@
 
 package metamodel_
 
-// Scenarios_ contains field name constants for Scenarios
-var Scenarios_ = struct {
+import (
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Scenarios_Owner contains field name constants for the Scenarios.Owner document
+type Scenarios_Owner struct {
+	Field[any]
+	Name  StringField[string]
+	Email StringField[string]
+}
+
+// WithOwner returns the fields of the Scenarios.Owner document qualified by the
+// table or alias val.
+func (d Scenarios_Owner) WithOwner(val string) Scenarios_Owner {
+	d.Field = d.Field.WithOwner(val)
+	d.Name = d.Name.WithOwner(val)
+	d.Email = d.Email.WithOwner(val)
+	return d
+}
+
+func (d *Scenarios_Owner) rebase(from, to Field[any]) {
+	d.Field.rebase(from, to)
+	d.Name.rebase(from, to)
+	d.Email.rebase(from, to)
+}
+
+// ScenariosMetamodel is the type of Scenarios_.
+type ScenariosMetamodel struct {
 	TableName   string
-	Status      Field
-	Description Field
-}{
+	FeatureName StringField[string]
+	ScenarioID  NumberField[int]
+	Status      StringField[string]
+	Description StringField[string]
+	SkippedTag  StringField[string]
+	Owner       Scenarios_Owner
+}
+
+// As returns a copy of Scenarios_ whose fields are qualified by alias, for
+// self-joins and queries that reference the table more than once.
+// Example: Scenarios_.As("t")
+func (m ScenariosMetamodel) As(alias string) ScenariosMetamodel {
+	m.FeatureName = m.FeatureName.WithOwner(alias)
+	m.ScenarioID = m.ScenarioID.WithOwner(alias)
+	m.Status = m.Status.WithOwner(alias)
+	m.Description = m.Description.WithOwner(alias)
+	m.SkippedTag = m.SkippedTag.WithOwner(alias)
+	m.Owner = m.Owner.WithOwner(alias)
+	return m
+}
+
+// Scenarios_ contains field name constants for Scenarios
+var Scenarios_ = ScenariosMetamodel{
 	TableName:   "scenarios",
-	Status:      Field{FieldName: "status", TableName: "scenarios"},
-	Description: Field{FieldName: "desc", TableName: "scenarios"},
+	FeatureName: StringField[string]{Field: Field[string]{FieldName: "featurename", TableName: "scenarios"}},
+	ScenarioID:  NumberField[int]{Field: Field[int]{FieldName: "scenarioid", TableName: "scenarios"}},
+	Status:      StringField[string]{Field: Field[string]{FieldName: "status", TableName: "scenarios"}},
+	Description: StringField[string]{Field: Field[string]{FieldName: "desc", TableName: "scenarios"}},
+	SkippedTag:  StringField[string]{Field: Field[string]{FieldName: "skippedtag", TableName: "scenarios"}},
+	Owner: Scenarios_Owner{
+		Field: Field[any]{FieldName: "owner", TableName: "scenarios"},
+		Name:  StringField[string]{Field: Field[string]{FieldName: "owner.name", TableName: "scenarios"}},
+		Email: StringField[string]{Field: Field[string]{FieldName: "owner.email", TableName: "scenarios"}},
+	},
 }
 
-// AnotherModel_ contains field name constants for AnotherModel
-var AnotherModel_ = struct {
+func init() {
+	registerMgoIndexes(Scenarios_.TableName, Scenarios_.Indexes)
+}
+
+// Indexes returns the MongoDB indexes declared on Scenarios, created
+// in the Scenarios_.TableName collection by EnsureIndexes.
+func (m ScenariosMetamodel) Indexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: m.Status.mgoKey(), Value: 1},
+				{Key: m.ScenarioID.mgoKey(), Value: -1},
+			},
+			Options: options.Index(),
+		},
+	}
+}
+
+// OwnerMetamodel is the type of Owner_.
+type OwnerMetamodel struct {
 	TableName string
-	UserName  Field
-}{
-	TableName: "scenarios",
-	UserName:  Field{FieldName: "user_name", TableName: "scenarios"},
+	Name      StringField[string]
+	Email     StringField[string]
+}
+
+// As returns a copy of Owner_ whose fields are qualified by alias, for
+// self-joins and queries that reference the table more than once.
+// Example: Owner_.As("t")
+func (m OwnerMetamodel) As(alias string) OwnerMetamodel {
+	m.Name = m.Name.WithOwner(alias)
+	m.Email = m.Email.WithOwner(alias)
+	return m
+}
+
+// Owner_ contains field name constants for Owner
+var Owner_ = OwnerMetamodel{
+	TableName: "owners",
+	Name:      StringField[string]{Field: Field[string]{FieldName: "name", TableName: "owners"}},
+	Email:     StringField[string]{Field: Field[string]{FieldName: "email", TableName: "owners"}},
+}
+
+// AnotherModelMetamodel is the type of AnotherModel_.
+type AnotherModelMetamodel struct {
+	TableName string
+	UserID    StringField[string]
+	UserName  StringField[string]
+}
+
+// As returns a copy of AnotherModel_ whose fields are qualified by alias, for
+// self-joins and queries that reference the table more than once.
+// Example: AnotherModel_.As("t")
+func (m AnotherModelMetamodel) As(alias string) AnotherModelMetamodel {
+	m.UserID = m.UserID.WithOwner(alias)
+	m.UserName = m.UserName.WithOwner(alias)
+	return m
+}
+
+// AnotherModel_ contains field name constants for AnotherModel
+var AnotherModel_ = AnotherModelMetamodel{
+	TableName: "another_models",
+	UserID:    StringField[string]{Field: Field[string]{FieldName: "userid", TableName: "another_models"}},
+	UserName:  StringField[string]{Field: Field[string]{FieldName: "user_name", TableName: "another_models"}},
 }
@@ -2,9 +2,11 @@ package main
 
 import (
 	"fmt"
+	"time"
 
 	metamodel_ "github.com/namnv2496/exmaple/generated"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func main() {
@@ -12,23 +14,177 @@ func main() {
 	fmt.Println("Scenarios.TableName: ", metamodel_.Scenarios_.TableName)
 	fmt.Println("Scenarios.Status: ", metamodel_.Scenarios_.Status)
 	fmt.Println("Feature.ScenarioID: ", metamodel_.Feature_.ScenarioID)
+	fmt.Println("Feature.Description.BSON: ", metamodel_.Feature_.Description.BSON)
+	fmt.Println(metamodel_.Feature_.TagNames.JSONToBSON("description"))
+	fmt.Println("Scenarios.Owner.Name: ", metamodel_.Scenarios_.Owner.Name.MgoEq("namnv"))
 	fmt.Println("AnotherModel.UserName: ", metamodel_.AnotherModel_.UserName)
 
 	// build gorm query
 	fmt.Println(metamodel_.GormTest_.FeatureName.Equal("1"))
-	fmt.Println(metamodel_.GormTest_.FeatureName.Equal(2))
+	fmt.Println(metamodel_.GormTest_.Type.Equal(2))
 	fmt.Println(metamodel_.GormTest_.FeatureName.EqualString("5"))
-	fmt.Println(metamodel_.GormTest_.FeatureName.EqualString(10))
-	fmt.Println(metamodel_.GormTest_.FeatureName.WithDefaultOwner().Equal(1000))
-	fmt.Println(metamodel_.GormTest_.FeatureName.WithOwner("features").Equal(1000))
+	fmt.Println(metamodel_.GormTest_.Type.EqualString(10))
+	fmt.Println(metamodel_.GormTest_.FeatureName.Like("feat%"))
+	fmt.Println(metamodel_.GormTest_.Type.BetweenString(1, 3))
+	fmt.Println(metamodel_.GormTest_.Type.WithDefaultOwner().Equal(1000))
+	fmt.Println(metamodel_.GormTest_.FeatureName.WithOwner("features").Equal("1000"))
 	fmt.Println(metamodel_.GormTest_.FeatureName.WithOwnerString("features"))
 
+	fmt.Println(metamodel_.GormTest_.Id.EqField(metamodel_.EmbeddedEntity_.ParentId))
+	fmt.Println(metamodel_.GormTest_.Id.EqFieldString(metamodel_.EmbeddedEntity_.ParentId))
 	fmt.Println(metamodel_.Join("table", metamodel_.GormTest_.FeatureName.EqualString("1")))
 	fmt.Println("select " + metamodel_.Columns(
 		metamodel_.Scenarios_.Status.String(),
 		metamodel_.Scenarios_.Description.String(),
 	))
 
+	// build parameterized SQL for db.Raw or database/sql
+	query, args := metamodel_.NewQueryBuilder(metamodel_.GormTest_.TableName).
+		Select(
+			metamodel_.GormTest_.Id,
+			metamodel_.GormTest_.IsActive,
+			metamodel_.GormTest_.PriceUnit,
+			metamodel_.GormTest_.Type,
+		).
+		Where(
+			metamodel_.GormTest_.IsActive.IsTrue(),
+			metamodel_.GormTest_.FeatureName.Equal("test"),
+			metamodel_.GormTest_.Type.In(1, 2),
+		).
+		OrderBy(metamodel_.GormTest_.Id.Asc()).
+		Build()
+	fmt.Println(query, args)
+
+	// the same query for SQL Server, paginated
+	query, args = metamodel_.NewQueryBuilder(metamodel_.GormTest_.TableName).
+		WithDialect(metamodel_.SQLServer).
+		Select(metamodel_.GormTest_.Id).
+		Where(metamodel_.GormTest_.FeatureName.Equal("test")).
+		OrderBy(metamodel_.GormTest_.Id.Asc()).
+		Limit(10).
+		Offset(20).
+		Build()
+	fmt.Println(query, args)
+
+	// join on metamodel fields; columns are qualified by their table
+	query, args = metamodel_.NewQueryBuilder(metamodel_.GormTest_.TableName).
+		Select(metamodel_.GormTest_.Id, metamodel_.EmbeddedEntity_.Value).
+		LeftJoin(metamodel_.EmbeddedEntity_.TableName+" e",
+			metamodel_.On(metamodel_.GormTest_.Id, metamodel_.EmbeddedEntity_.ParentId)).
+		Where(metamodel_.GormTest_.IsActive.IsTrue()).
+		Build()
+	fmt.Println(query, args)
+
+	// self-join through aliased copies of the metamodel
+	child, parent := metamodel_.GormTest_.As("c"), metamodel_.GormTest_.As("p")
+	query, args = metamodel_.NewQueryBuilder(metamodel_.GormTest_.TableName+" c").
+		Select(child.FeatureName, parent.FeatureName).
+		InnerJoin(metamodel_.GormTest_.TableName+" p", child.Type.EqField(parent.Type)).
+		Where(child.Id.NeField(parent.Id)).
+		Build()
+	fmt.Println(query, args)
+
+	// keyset pagination with a signed cursor from the last row of a page
+	codec, err := metamodel_.NewCursorCodec([]byte("example secret, use a random key"))
+	if err != nil {
+		panic(err)
+	}
+	token, _ := codec.Encode(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), uint(42))
+	cursor, err := codec.Decode(token)
+	if err != nil {
+		panic(err)
+	}
+	query, args = metamodel_.NewQueryBuilder(metamodel_.GormTest_.TableName).
+		WithDialect(metamodel_.Postgres).
+		After(cursor, metamodel_.GormTest_.CreatedAt.Desc(), metamodel_.GormTest_.Id.Desc()).
+		Limit(20).
+		Build()
+	fmt.Println(query, args)
+
+	// INSERT / UPDATE / DELETE
+	query, args, err = metamodel_.NewUpdateBuilder(metamodel_.GormTest_.TableName).
+		Set(metamodel_.GormTest_.FeatureName, "renamed").
+		Incr(metamodel_.GormTest_.Type, 1).
+		Where(metamodel_.GormTest_.Id.Equal(7)).
+		Build()
+	fmt.Println(query, args, err)
+	_, _, err = metamodel_.NewDeleteBuilder(metamodel_.GormTest_.TableName).Build()
+	fmt.Println(err)
+
+	// upsert on a unique column
+	upsert := metamodel_.OnConflict(metamodel_.EmbeddedEntity_.Uuid).DoUpdate(metamodel_.EmbeddedEntity_.Value)
+	query, args, err = metamodel_.NewInsertBuilder(metamodel_.EmbeddedEntity_.TableName).
+		WithDialect(metamodel_.MySQL).
+		Columns(metamodel_.EmbeddedEntity_.Uuid, metamodel_.EmbeddedEntity_.Value).
+		Values("0b7d6a8e-9d7c-4f43-9a53-0d3b3c1c2f7e", 10).
+		OnConflict(upsert).
+		Build()
+	fmt.Println(query, args, err)
+
+	// aggregates
+	total := metamodel_.GormTest_.Id.Count()
+	query, args = metamodel_.NewQueryBuilder(metamodel_.GormTest_.TableName).
+		Select(metamodel_.GormTest_.Type, total.As("total")).
+		Where(metamodel_.GormTest_.FeatureName.Lower().Equal("test")).
+		GroupBy(metamodel_.GormTest_.Type).
+		Having(total.Gt(5)).
+		OrderByExpr(total.Desc()).
+		Build()
+	fmt.Println(query, args)
+
+	// subqueries
+	parents := metamodel_.NewQueryBuilder(metamodel_.EmbeddedEntity_.TableName).
+		Select(metamodel_.EmbeddedEntity_.ParentId).
+		Where(metamodel_.EmbeddedEntity_.Value.Gt(10))
+	query, args = metamodel_.NewQueryBuilder(metamodel_.GormTest_.TableName).
+		WithDialect(metamodel_.Postgres).
+		Where(metamodel_.GormTest_.IsActive.IsTrue(), metamodel_.GormTest_.Id.InQuery(parents)).
+		Build()
+	fmt.Println(query, args)
+
+	// latest row of every type through a CTE and a window function
+	latest := metamodel_.NewQueryBuilder(metamodel_.GormTest_.TableName).
+		Select(
+			metamodel_.GormTest_.Id,
+			metamodel_.RowNumber().
+				PartitionBy(metamodel_.GormTest_.Type).
+				OrderBy(metamodel_.GormTest_.CreatedAt.Desc()).
+				As("position"),
+		)
+	query, args = metamodel_.NewQueryBuilder("latest").
+		With("latest", latest).
+		Where(clause.Expr{SQL: "position = ?", Vars: []any{1}}).
+		Build()
+	fmt.Println(query, args)
+
+	// one MongoDB update document for changes to several fields
+	fmt.Println(metamodel_.MgoUpdate(
+		metamodel_.Scenarios_.Status.MgoSet("done"),
+		metamodel_.Scenarios_.ScenarioID.MgoInc(1),
+		metamodel_.Scenarios_.Owner.Name.MgoSet("namnv"),
+	))
+
+	// MongoDB aggregation counting the scenarios of each status
+	pipeline := metamodel_.NewMgoPipeline().
+		Match(metamodel_.Scenarios_.Status.MgoNe("deleted")).
+		Group(metamodel_.Scenarios_.Status).Count("total").
+		Project(metamodel_.MgoName("total")).
+		Build()
+	fmt.Println(pipeline)
+
+	// MongoDB find with its sort, projection and paging
+	open := metamodel_.NewMgoQuery(metamodel_.Scenarios_.Status.MgoEq("open")).
+		Sort(metamodel_.Scenarios_.ScenarioID.MgoDesc()).
+		Include(metamodel_.Scenarios_.Status, metamodel_.Scenarios_.Description).
+		Limit(10)
+	fmt.Println(open.Filter(), len(open.FindOptions().List())) // coll.Find(ctx, open.Filter(), open.FindOptions())
+
+	// array filters and element paths
+	fmt.Println(metamodel_.GormTest_.GormElement.MgoSize(0), metamodel_.GormTest_.GormElement.At(0).MgoExists(true))
+
+	// MongoDB indexes declared on the structs, created by metamodel_.EnsureIndexes(ctx, mongoDB)
+	fmt.Println(metamodel_.Scenarios_.Indexes()[0].Keys, metamodel_.Feature_.Indexes()[0].Keys)
+
 	var db *gorm.DB
 	var results []map[string]interface{} // or []metamodel_.GormTest
 
@@ -45,20 +201,4 @@ func main() {
 		Order(metamodel_.GormTest_.Id.AscString()).
 		Find(&results)
 	fmt.Println(db.Statement.SQL.String()) // or use db.Raw(query).Scan(&results)
-	// or
-	// query := metamodel_.NewQueryBuilder(metamodel_.GormTest_.TableName).
-	// 	Select(
-	// 		metamodel_.GormTest_.Id.String(),
-	// 		metamodel_.GormTest_.IsActive.String(),
-	// 		metamodel_.Feature_.FeatureName.String(),
-	// 		metamodel_.GormTest_.PriceUnit.String(),
-	// 		metamodel_.GormTest_.Type.String(),
-	// 	).
-	// 	Where(
-	// 		metamodel_.AndString(metamodel_.GormTest_.IsActive.IsTrueString()),
-	// 		metamodel_.AndString(metamodel_.GormTest_.FeatureName.EqualString("test")),
-	// 	).
-	// 	OrderBy(metamodel_.GormTest_.Id.AscString()).
-	// 	Build()
-	// fmt.Println(query)
 }
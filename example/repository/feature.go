@@ -1,11 +1,11 @@
 package repository
 
-//go:generate metamodel -source=$GOFILE -destination=../generated/ -tag=json -packageName=metamodel -tableName=features
+//go:generate metamodel -source=$GOFILE -destination=../generated/ -tag=json,bson -packageName=metamodel -tableName=features
 type Feature struct {
 	FeatureName string `json:"feature_name,omitempty"`
 	ScenarioID  int    `json:"scenario_id"`
-	Status      string `bson:"status"`
+	Status      string `bson:"status" metamodel:"index,sparse"`
 	Description string `json:"description,omitempty" bson:"desc"`
-	IgnoreMe    string // no tag, should be ignored
+	IgnoreMe    string `json:"-"` // skip tag
 	SkippedTag  string `json:"-"` // skip tag
 }
@@ -4,7 +4,7 @@ import (
 	"github.com/namnv2496/exmaple/entity"
 )
 
-//go:generate metamodel -source=$GOFILE -destination=../generated/ -tag=gorm -packageName=metamodel -tableName=gorm_tests
+//go:generate metamodel -source=$GOFILE -destination=../generated/ -tag=gorm -packageName=metamodel
 type GormTest struct {
 	entity.Entity  `gorm:"embedded"`
 	FeatureName    string            `gorm:"column:feature_name;not null"`
@@ -13,7 +13,11 @@ type GormTest struct {
 	GormElement    []GormElement     `gorm:"column:gorm_element"`
 	PriceUnit      string            `gorm:"column:price_unit;type:varchar(250);default:'đ';"`
 	EmbeddedEntity []*EmbeddedEntity `gorm:"many2many:embedded_entity;"`
-	IgnoreMe       uint32            `gorm:"->"`
+	IgnoreMe       uint32            `gorm:"-"`
+}
+
+func (GormTest) TableName() string {
+	return "gorm_tests"
 }
 
 type GormElement struct {
@@ -26,3 +30,7 @@ type EmbeddedEntity struct {
 	ParentId      uint32 `gorm:"column:parent_id;default:0;"`
 	Value         uint32 `gorm:"column:value;not null;"`
 }
+
+func (EmbeddedEntity) TableName() string {
+	return "embedded_entity"
+}
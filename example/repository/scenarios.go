@@ -1,14 +1,21 @@
 package repository
 
-//go:generate metamodel -source=scenarios.go -destination=../generated/ -tag=bson -packageName=metamodel -tableName=scenarios
+//go:generate metamodel -source=scenarios.go -destination=../generated/ -tag=bson -packageName=metamodel -tableName=Scenarios=scenarios
 
+//metamodel:index=Status,-ScenarioID
 type Scenarios struct {
 	FeatureName string `json:"feature_name,omitempty"`
 	ScenarioID  int    `json:"scenario_id"`
 	Status      string `bson:"status"`
 	Description string `json:"description,omitempty" bson:"desc"`
-	IgnoreMe    string // no tag, should be ignored
-	SkippedTag  string `json:"-"` // skip tag
+	IgnoreMe    string `bson:"-"` // skip tag
+	SkippedTag  string `json:"-"` // stored by the Mongo driver as "skippedtag"
+	Owner       Owner  `bson:"owner"`
+}
+
+type Owner struct {
+	Name  string `bson:"name"`
+	Email string // stored by the Mongo driver as "email"
 }
 
 type AnotherModel struct {
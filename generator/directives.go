@@ -0,0 +1,78 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+)
+
+const directivePrefix = "//metamodel:"
+
+// structDirectives holds the //metamodel: annotations of a type declaration:
+//
+//	//metamodel:table=user_accounts
+//	//metamodel:tag=bson
+//	//metamodel:name=Account
+//	//metamodel:skip
+//	//metamodel:index=Email unique
+type structDirectives struct {
+	Skip    bool        // do not generate a metamodel for the struct
+	Table   string      // table name, overriding TableName() and -tableName
+	Tag     string      // struct tag to read column names from, overriding -tag
+	Name    string      // name of the generated metamodel variable, without the trailing "_"
+	Indexes []IndexMeta // MongoDB indexes of the collection, one directive each
+}
+
+// empty reports whether no directive was given.
+func (d structDirectives) empty() bool {
+	return !d.Skip && d.Table == "" && d.Tag == "" && d.Name == "" && len(d.Indexes) == 0
+}
+
+// parseDirectives reads the //metamodel: lines of the given comment groups.
+// Unknown or malformed directives are errors so that typos do not go unnoticed.
+func parseDirectives(docs ...*ast.CommentGroup) (structDirectives, error) {
+	var d structDirectives
+	for _, doc := range docs {
+		if doc == nil {
+			continue
+		}
+		for _, comment := range doc.List {
+			directive, ok := strings.CutPrefix(comment.Text, directivePrefix)
+			if !ok {
+				continue
+			}
+			key, value, hasValue := strings.Cut(strings.TrimSpace(directive), "=")
+			value = strings.TrimSpace(value)
+			if key == "skip" {
+				if hasValue {
+					return d, fmt.Errorf("directive %s takes no value", comment.Text)
+				}
+				d.Skip = true
+				continue
+			}
+			if !hasValue || value == "" {
+				return d, fmt.Errorf("directive %s requires a value", comment.Text)
+			}
+			switch key {
+			case "table":
+				d.Table = value
+			case "tag":
+				d.Tag = value
+			case "name":
+				if !ast.IsExported(value) {
+					return d, fmt.Errorf("directive %s must name an exported identifier", comment.Text)
+				}
+				d.Name = value
+			case "index":
+				idx, err := parseIndexDirective(value)
+				if err != nil {
+					return d, fmt.Errorf("directive %s: %w", comment.Text, err)
+				}
+				d.Indexes = append(d.Indexes, idx)
+			default:
+				return d, fmt.Errorf("unknown directive %s", comment.Text)
+			}
+		}
+	}
+	return d, nil
+}
@@ -0,0 +1,61 @@
+package generator
+
+import (
+	"go/ast"
+	"reflect"
+	"testing"
+)
+
+// ---- parseDirectives ------------------------------------------------------------
+
+func commentGroup(lines ...string) *ast.CommentGroup {
+	cg := &ast.CommentGroup{}
+	for _, line := range lines {
+		cg.List = append(cg.List, &ast.Comment{Text: line})
+	}
+	return cg
+}
+
+func TestParseDirectives(t *testing.T) {
+	doc := commentGroup(
+		"// User is an account.",
+		"//go:generate metamodel -source=$GOFILE",
+		"//metamodel:table=user_accounts",
+		"//metamodel:tag=bson",
+		"//metamodel:name=Account",
+		"//metamodel:index=Email unique",
+	)
+	got, err := parseDirectives(doc, nil)
+	if err != nil {
+		t.Fatalf("parseDirectives() error = %v", err)
+	}
+	want := structDirectives{Table: "user_accounts", Tag: "bson", Name: "Account", Indexes: []IndexMeta{
+		{Keys: []IndexKey{{Field: "Email"}}, Unique: true},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDirectives() = %+v, want %+v", got, want)
+	}
+
+	got, err = parseDirectives(commentGroup("//metamodel:skip"))
+	if err != nil {
+		t.Fatalf("parseDirectives() error = %v", err)
+	}
+	if !got.Skip {
+		t.Error("expected Skip to be set")
+	}
+}
+
+func TestParseDirectives_Invalid(t *testing.T) {
+	for _, line := range []string{
+		"//metamodel:tabel=users",
+		"//metamodel:table=",
+		"//metamodel:tag",
+		"//metamodel:skip=true",
+		"//metamodel:name=account",
+		"//metamodel:index=Name uniq",
+	} {
+		if _, err := parseDirectives(commentGroup(line)); err == nil {
+			t.Errorf("parseDirectives(%q) expected error, got nil", line)
+		}
+	}
+}
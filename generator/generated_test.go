@@ -0,0 +1,1052 @@
+package generator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// ---- Generated code (compiled and run) --------------------------------------------
+
+// sqlModelsFixture is the gorm model set the generated SQL builders are run against.
+const sqlModelsFixture = `package models
+
+import "time"
+
+type Item struct {
+	ID        int       ` + "`gorm:\"column:id\"`" + `
+	Name      string    ` + "`gorm:\"column:name\"`" + `
+	Active    bool      ` + "`gorm:\"column:active\"`" + `
+	Price     float64   ` + "`gorm:\"column:price\"`" + `
+	CreatedAt time.Time ` + "`gorm:\"column:created_at\"`" + `
+}
+
+type Order struct {
+	ID     int ` + "`gorm:\"column:id\"`" + `
+	ItemID int ` + "`gorm:\"column:item_id\"`" + `
+	Amount int ` + "`gorm:\"column:amount\"`" + `
+}
+`
+
+// mongoModelsFixture is the model set the generated MongoDB helpers are run
+// against, named by its json tags with the bson keys alongside.
+const mongoModelsFixture = `package models
+
+import "time"
+
+type Scenario struct {
+	ID        string    ` + "`json:\"id\" bson:\"_id\"`" + `
+	OwnerID   string    ` + "`json:\"ownerId\" bson:\"owner_id\"`" + `
+	Name      string    ` + "`json:\"name\" bson:\"name\"`" + `
+	Score     int       ` + "`json:\"score\" bson:\"score\"`" + `
+	Tags      []string  ` + "`json:\"tags\" bson:\"tags\"`" + `
+	UpdatedAt time.Time ` + "`json:\"updatedAt\" bson:\"updated_at\"`" + `
+	Owner     Owner     ` + "`json:\"owner\" bson:\"owner\"`" + `
+	Items     []Item    ` + "`json:\"items\" bson:\"items\"`" + `
+}
+
+type Item struct {
+	Name   string  ` + "`json:\"name\" bson:\"name\"`" + `
+	Qty    int     ` + "`json:\"qty\" bson:\"qty\"`" + `
+	Owners []Owner ` + "`json:\"owners\" bson:\"owners\"`" + `
+}
+
+type Owner struct {
+	ID   string ` + "`json:\"id\" bson:\"_id\"`" + `
+	Name string ` + "`json:\"name\" bson:\"name\"`" + `
+}
+`
+
+// runGenerated generates the metamodel of models into a scratch module that
+// uses the example module's dependencies, then runs body as its main function
+// with the metamodel package imported as m. It returns the program output.
+func runGenerated(t *testing.T, models, tag, body string) string {
+	t.Helper()
+	return runGeneratedWith(t, models, tag, "", body)
+}
+
+// runGeneratedWith is runGenerated with extra top-level declarations in the
+// main package. gorm, clause and a DryRun gorm.DB (dryRunDB) are available.
+func runGeneratedWith(t *testing.T, models, tag, decls, body string) string {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("compiles generated code")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not available")
+	}
+	exampleMod, err := os.ReadFile("../example/go.mod")
+	if err != nil {
+		t.Skipf("example module not available: %v", err)
+	}
+	exampleSum, err := os.ReadFile("../example/go.sum")
+	if err != nil {
+		t.Skipf("example module not available: %v", err)
+	}
+
+	dir := t.TempDir()
+	_, requires, _ := strings.Cut(string(exampleMod), "\nrequire")
+	mustWriteFile(t, filepath.Join(dir, "go.mod"), "module example.com/gen\n\ngo 1.25\n\nrequire"+requires)
+	mustWriteFile(t, filepath.Join(dir, "go.sum"), string(exampleSum))
+	mustMkdir(t, filepath.Join(dir, "models"))
+	mustWriteFile(t, filepath.Join(dir, "models", "models.go"), models)
+	cfg := Config{
+		Source:      filepath.Join(dir, "models", "models.go"),
+		Destination: filepath.Join(dir, "metamodel") + "/",
+		PackageName: "metamodel",
+		Tag:         tag,
+	}
+	if err := Generate(cfg); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	mustWriteFile(t, filepath.Join(dir, "main.go"), `package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	m "example.com/gen/metamodel"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+var (
+	_ context.Context
+	_ = fmt.Println
+	_ = strings.Split
+	_ = errors.Is
+	_ = time.Now
+	_ bson.D
+	_ mongo.Pipeline
+	_ options.FindOptions
+	_ clause.Expression
+)
+
+`+dryRunDialector+decls+`
+
+func main() {
+`+body+`
+}
+`)
+	cmd := exec.Command(goBin, "run", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off", "GOWORK=off")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if strings.Contains(string(out), "module lookup disabled") && !strings.Contains(string(out), "example.com/gen") {
+			t.Skipf("dependencies not in the module cache:\n%s", out)
+		}
+		t.Fatalf("go run: %v\n%s", err, out)
+	}
+	return string(out)
+}
+
+// dryRunDialector renders gorm statements without a database, quoting
+// each part of an identifier with double quotes, as the gorm drivers do, and
+// binding ? placeholders.
+const dryRunDialector = `
+type dryRun struct{}
+
+func (dryRun) Name() string { return "dryrun" }
+func (dryRun) Initialize(db *gorm.DB) error {
+	callbacks.RegisterDefaultCallbacks(db, &callbacks.Config{})
+	return nil
+}
+func (dryRun) Migrator(*gorm.DB) gorm.Migrator         { return nil }
+func (dryRun) DataTypeOf(*schema.Field) string         { return "" }
+func (dryRun) DefaultValueOf(*schema.Field) clause.Expression {
+	return clause.Expr{SQL: "DEFAULT"}
+}
+func (dryRun) BindVarTo(w clause.Writer, _ *gorm.Statement, _ any) { w.WriteByte('?') }
+func (dryRun) QuoteTo(w clause.Writer, s string) {
+	for i, part := range strings.Split(s, ".") {
+		if i > 0 {
+			w.WriteByte('.')
+		}
+		w.WriteByte('"')
+		w.WriteString(part)
+		w.WriteByte('"')
+	}
+}
+func (dryRun) Explain(sql string, _ ...any) string { return sql }
+
+func dryRunDB() *gorm.DB {
+	db, err := gorm.Open(dryRun{}, &gorm.Config{DryRun: true})
+	if err != nil {
+		panic(err)
+	}
+	return db
+}
+
+// gormSQL returns the SELECT gorm builds for the given query on table.
+func gormSQL(table string, query func(*gorm.DB) *gorm.DB) string {
+	var rows []map[string]any
+	stmt := query(dryRunDB().Table(table)).Find(&rows).Statement
+	return fmt.Sprint(stmt.SQL.String(), " ", stmt.Vars)
+}
+`
+
+// assertLines compares program output line by line.
+func assertLines(t *testing.T, got string, want ...string) {
+	t.Helper()
+	lines := strings.Split(strings.TrimRight(got, "\n"), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(want), got)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("line %d:\n got: %s\nwant: %s", i+1, lines[i], want[i])
+		}
+	}
+}
+
+func TestGenerated_QueryBuilder(t *testing.T) {
+	out := runGenerated(t, sqlModelsFixture, "gorm", `
+	query, args := m.NewQueryBuilder(m.Item_.TableName).
+		Select(m.Item_.ID, m.Item_.Name).
+		Where(
+			m.Item_.Name.Equal("x' OR '1'='1"),
+			m.Or(m.Item_.Price.Gt(10), m.Item_.Active.IsTrue()),
+			m.Item_.ID.In(1, 2, 3),
+		).
+		GroupBy(m.Item_.Name).
+		OrderBy(m.Item_.CreatedAt.Desc()).
+		Build()
+	fmt.Println(query)
+	fmt.Println(args...)
+
+	query, args = m.NewQueryBuilder(m.Item_.TableName).
+		WhereString(m.Item_.Name.EqualString("'raw'")).
+		Build()
+	fmt.Println(query, len(args))
+`)
+	assertLines(t, out,
+		"SELECT id, name FROM items WHERE name = ? AND (price > ? OR active = ?) AND id IN (?,?,?) GROUP BY name ORDER BY created_at DESC",
+		"x' OR '1'='1 10 true 1 2 3",
+		"SELECT * FROM items WHERE  name = 'raw'  0",
+	)
+}
+
+func TestGenerated_Dialects(t *testing.T) {
+	out := runGenerated(t, sqlModelsFixture, "gorm", `
+	for _, d := range []m.Dialect{m.GenericSQL, m.Postgres, m.MySQL, m.SQLite, m.SQLServer} {
+		query, args := m.NewQueryBuilder(m.Item_.TableName).
+			WithDialect(d).
+			Select(m.Item_.ID).
+			Where(m.Item_.Name.Equal("a"), m.Item_.Price.Between(1, 2)).
+			OrderBy(m.Item_.ID.Asc()).
+			Limit(10).
+			Offset(20).
+			Build()
+		fmt.Println(d.Name()+":", query, len(args))
+	}
+	q, _ := m.NewQueryBuilder("t").WithDialect(m.SQLServer).Limit(5).Build()
+	fmt.Println(q)
+	q, _ = m.NewQueryBuilder("t").WithDialect(m.MySQL).Offset(5).Build()
+	fmt.Println(q)
+	fmt.Println(m.Postgres.QuoteIdentifier(`+"`"+`a"b.c`+"`"+`), m.SQLServer.QuoteIdentifier("t.*"))
+
+	m.DefaultDialect = m.SQLServer
+	fmt.Println(m.Item_.Active.IsTrueString())
+`)
+	assertLines(t, out,
+		"generic: SELECT id FROM items WHERE name = ? AND (price BETWEEN ? AND ?) ORDER BY id LIMIT 10 OFFSET 20 3",
+		`postgres: SELECT "id" FROM "items" WHERE "name" = $1 AND ("price" BETWEEN $2 AND $3) ORDER BY "id" LIMIT 10 OFFSET 20 3`,
+		"mysql: SELECT `id` FROM `items` WHERE `name` = ? AND (`price` BETWEEN ? AND ?) ORDER BY `id` LIMIT 10 OFFSET 20 3",
+		"sqlite: SELECT `id` FROM `items` WHERE `name` = ? AND (`price` BETWEEN ? AND ?) ORDER BY `id` LIMIT 10 OFFSET 20 3",
+		"sqlserver: SELECT [id] FROM [items] WHERE [name] = @p1 AND ([price] BETWEEN @p2 AND @p3) ORDER BY [id] OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY 3",
+		"SELECT TOP (5) * FROM [t]",
+		"SELECT * FROM `t` LIMIT 18446744073709551615 OFFSET 5",
+		`"a""b"."c" [t].*`,
+		" [active] = 1 ",
+	)
+}
+
+func TestGenerated_GormExpressions(t *testing.T) {
+	out := runGenerated(t, sqlModelsFixture, "gorm", `
+	fmt.Println(gormSQL(m.Item_.TableName, func(db *gorm.DB) *gorm.DB {
+		return db.Where(m.Item_.Name.Equal("a")).
+			Where(m.Item_.ID.In(1, 2)).
+			Where(m.Item_.Price.Between(1, 5)).
+			Where(m.Item_.Name.Like("b%")).
+			Where(m.Or(m.Item_.Active.IsTrue(), m.Item_.Price.Gt(3))).
+			Order(m.Item_.ID.Desc())
+	}))
+	fmt.Println(m.Item_.Name.Equal("a"))
+`)
+	assertLines(t, out,
+		`SELECT * FROM "items" WHERE "name" = ? AND "id" IN (?,?) AND ("price" BETWEEN ? AND ?) AND "name" LIKE ? AND ("active" = ? OR "price" > ?) ORDER BY "id" DESC [a 1 2 1 5 b% true 3]`,
+		"{name a}",
+	)
+}
+
+func TestGenerated_Joins(t *testing.T) {
+	out := runGenerated(t, sqlModelsFixture, "gorm", `
+	query, args := m.NewQueryBuilder(m.Order_.TableName).
+		WithDialect(m.Postgres).
+		Select(m.Order_.ID, m.Item_.Name).
+		InnerJoin(m.Item_.TableName, m.On(m.Order_.ItemID, m.Item_.ID)).
+		Where(m.Item_.Active.IsTrue(), m.Order_.Amount.Gt(1)).
+		GroupBy(m.Item_.Name).
+		OrderBy(m.Item_.Name.WithDefaultOwner().Asc()).
+		Build()
+	fmt.Println(query, args)
+
+	query, _ = m.NewQueryBuilder(m.Order_.TableName+" o").
+		Select(m.Order_.ID).
+		LeftJoin(m.Item_.TableName+" AS i", m.On(m.Order_.ItemID, m.Item_.ID), m.Item_.Price.Lt(5)).
+		RightJoin("warehouses w").
+		OrderBy(m.Order_.ID.WithOwner("o").Desc(), m.Item_.ID.WithDefaultOwner().Asc(), m.Item_.Name.Asc()).
+		Build()
+	fmt.Println(query)
+`)
+	assertLines(t, out,
+		`SELECT "orders"."id", "items"."name" FROM "orders" INNER JOIN "items" ON "orders"."item_id" = "items"."id" WHERE "items"."active" = $1 AND "orders"."amount" > $2 GROUP BY "items"."name" ORDER BY "items"."name" [true 1]`,
+		"SELECT o.id FROM orders o LEFT JOIN items i ON o.item_id = i.id AND i.price < ? RIGHT JOIN warehouses w ORDER BY o.id DESC, i.id, name",
+	)
+}
+
+func TestGenerated_FieldComparisons(t *testing.T) {
+	out := runGenerated(t, sqlModelsFixture, "gorm", `
+	fmt.Println(gormSQL(m.Order_.TableName, func(db *gorm.DB) *gorm.DB {
+		return db.Joins("JOIN items ON ?", m.Order_.ItemID.EqField(m.Item_.ID)).
+			Where(m.Order_.Amount.GtField(m.Item_.Price)).
+			Where(m.Item_.CreatedAt.LteField(m.Item_.CreatedAt))
+	}))
+
+	query, _ := m.NewQueryBuilder(m.Order_.TableName).
+		WithDialect(m.MySQL).
+		Where(m.Order_.ID.NeField(m.Order_.ItemID), m.Order_.Amount.LtField(m.Item_.Price)).
+		Build()
+	fmt.Println(query)
+
+	query, _ = m.NewQueryBuilder(m.Order_.TableName+" o").
+		InnerJoin(m.Item_.TableName+" i", m.Order_.ItemID.EqField(m.Item_.ID)).
+		Where(m.Order_.Amount.GteField(m.Item_.Price)).
+		Build()
+	fmt.Println(query)
+
+	fmt.Println(m.Order_.ItemID.EqFieldString(m.Item_.ID))
+`)
+	assertLines(t, out,
+		`SELECT * FROM "orders" JOIN items ON "orders"."item_id" = "items"."id" WHERE "orders"."amount" > "items"."price" AND "items"."created_at" <= "items"."created_at" []`,
+		"SELECT * FROM `orders` WHERE `orders`.`id` <> `orders`.`item_id` AND `orders`.`amount` < `items`.`price`",
+		"SELECT * FROM orders o INNER JOIN items i ON o.item_id = i.id WHERE o.amount >= i.price",
+		" orders.item_id = items.id ",
+	)
+}
+
+func TestGenerated_Aliases(t *testing.T) {
+	out := runGenerated(t, sqlModelsFixture, "gorm", `
+	child, parent := m.Item_.As("c"), m.Item_.As("p")
+	query, args := m.NewQueryBuilder(m.Item_.TableName+" c").
+		WithDialect(m.Postgres).
+		Select(child.Name, parent.Name).
+		InnerJoin(m.Item_.TableName+" p", m.On(child.ID, parent.ID)).
+		Where(child.Price.GtField(parent.Price), parent.Active.IsTrue()).
+		OrderBy(parent.Name.Asc()).
+		Build()
+	fmt.Println(query, args)
+
+	fmt.Println(gormSQL(m.Item_.TableName+" c", func(db *gorm.DB) *gorm.DB {
+		return db.Select(child.ID.String()).Where(child.Name.Equal("x")).Order(child.ID.Desc())
+	}))
+
+	fmt.Println(m.Item_.Name.WithOwner("a").WithOwner("b").String())
+	fmt.Println(m.Item_.Name.WithOwner("a").WithDefaultOwner().String())
+	fmt.Println(m.Item_.Name.String(), m.Item_.As("c").TableName)
+	fmt.Println(child.Price.GtString(1))
+	fmt.Println(child.Name.WithOwnerString("p"), m.Item_.Name.WithOwnerString("p"), child.Name.WithDefaultOwnerString())
+`)
+	assertLines(t, out,
+		`SELECT "c"."name", "p"."name" FROM "items" "c" INNER JOIN "items" "p" ON "c"."id" = "p"."id" WHERE "c"."price" > "p"."price" AND "p"."active" = $1 ORDER BY "p"."name" [true]`,
+		`SELECT c.id FROM items c WHERE "c"."name" = ? ORDER BY "c"."id" DESC [x]`,
+		"b.name",
+		"items.name",
+		"name items",
+		" c.price > 1 ",
+		" p.name   p.name   items.name ",
+	)
+}
+
+func TestGenerated_MultiTagColumns(t *testing.T) {
+	models := `package models
+
+type User struct {
+	ID    int    ` + "`json:\"id\" bson:\"_id\" gorm:\"column:user_id\"`" + `
+	Email string ` + "`json:\"emailAddress\" bson:\"email\" gorm:\"column:email_address\"`" + `
+}
+`
+	out := runGenerated(t, models, "json,bson,gorm", `
+	query, args := m.NewQueryBuilder(m.User_.TableName).
+		Select(m.User_.ID, m.User_.Email).
+		Where(m.User_.Email.Equal("a@b.c")).
+		OrderBy(m.User_.ID.Desc()).
+		Build()
+	fmt.Println(query, args)
+
+	query, args, _ = m.NewUpdateBuilder(m.User_.TableName).
+		Set(m.User_.Email, "x@y.z").
+		Where(m.User_.ID.Equal(1)).
+		Build()
+	fmt.Println(query, args)
+
+	fmt.Println(gormSQL(m.User_.TableName, func(db *gorm.DB) *gorm.DB {
+		return db.Where(m.User_.Email.Equal("a@b.c")).Order(m.User_.ID.Asc())
+	}))
+	fmt.Println(m.User_.Email.String(), m.User_.Email.EqualString("x"), m.User_.Email.MgoEq("x"))
+`)
+	assertLines(t, out,
+		"SELECT user_id, email_address FROM users WHERE email_address = ? ORDER BY user_id DESC [a@b.c]",
+		"UPDATE users SET email_address = ? WHERE user_id = ? [x@y.z 1]",
+		`SELECT * FROM "users" WHERE "email_address" = ? ORDER BY "user_id" [a@b.c]`,
+		`email_address  email_address = x  {"email":{"$eq":"x"}}`,
+	)
+}
+
+func TestGenerated_KeysetPagination(t *testing.T) {
+	out := runGenerated(t, sqlModelsFixture, "gorm", `
+	codec, err := m.NewCursorCodec([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		panic(err)
+	}
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	token, err := codec.Encode(created, 42)
+	if err != nil {
+		panic(err)
+	}
+	cursor, err := codec.Decode(token)
+	fmt.Println(err, cursor[0].(time.Time).Equal(created), cursor[1].(int))
+
+	for _, d := range []m.Dialect{m.Postgres, m.SQLServer} {
+		query, args := m.NewQueryBuilder(m.Item_.TableName).
+			WithDialect(d).
+			Where(m.Item_.Active.IsTrue()).
+			After(cursor, m.Item_.CreatedAt.Desc(), m.Item_.ID.Desc()).
+			Limit(10).
+			Build()
+		fmt.Println(query, len(args))
+	}
+
+	query, args := m.NewQueryBuilder(m.Item_.TableName).
+		After(m.Cursor{"b", 7}, m.Item_.Name.Asc(), m.Item_.ID.Desc()).
+		Build()
+	fmt.Println(query, args)
+
+	query, _ = m.NewQueryBuilder(m.Item_.TableName).After(nil, m.Item_.ID.Asc()).Limit(10).Build()
+	fmt.Println(query)
+
+	for _, d := range []m.Dialect{m.Postgres, m.DefaultDialect} {
+		query, args = m.NewQueryBuilder(m.Order_.TableName+" o").
+			WithDialect(d).
+			InnerJoin(m.Item_.TableName, m.On(m.Order_.ItemID, m.Item_.ID)).
+			After(m.Cursor{3, 9}, m.Item_.ID.WithDefaultOwner().Asc(), m.Order_.ID.WithOwner("o").Asc()).
+			Build()
+		fmt.Println(query, args)
+	}
+
+	qb := m.NewQueryBuilder(m.Item_.TableName).After(m.Cursor{1}, m.Item_.Name.Asc(), m.Item_.ID.Asc())
+	qb.Build()
+	fmt.Println(errors.Is(qb.Err(), m.ErrInvalidCursor))
+
+	tampered := []byte(token)
+	tampered[len(tampered)-2] ^= 1
+	_, err = codec.Decode(string(tampered))
+	fmt.Println(errors.Is(err, m.ErrInvalidCursor))
+	other, _ := m.NewCursorCodec([]byte("another key of 32 bytes, or more"))
+	_, err = other.Decode(token)
+	fmt.Println(errors.Is(err, m.ErrInvalidCursor))
+	empty, err := codec.Decode("")
+	fmt.Println(len(empty), err)
+	for _, key := range [][]byte{nil, []byte("fifteen bytes!!")} {
+		_, err = m.NewCursorCodec(key)
+		fmt.Println(err)
+	}
+`)
+	assertLines(t, out,
+		"<nil> true 42",
+		`SELECT * FROM "items" WHERE "active" = $1 AND ("created_at", "id") < ($2, $3) ORDER BY "created_at" DESC, "id" DESC LIMIT 10 3`,
+		"SELECT TOP (10) * FROM [items] WHERE [active] = @p1 AND ([created_at] < @p2 OR ([created_at] = @p3 AND [id] < @p4)) ORDER BY [created_at] DESC, [id] DESC 4",
+		"SELECT * FROM items WHERE (name > ? OR (name = ? AND id < ?)) ORDER BY name, id DESC [b b 7]",
+		"SELECT * FROM items ORDER BY id LIMIT 10",
+		`SELECT * FROM "orders" "o" INNER JOIN "items" ON "o"."item_id" = "items"."id" WHERE ("items"."id", "o"."id") > ($1, $2) ORDER BY "items"."id", "o"."id" [3 9]`,
+		"SELECT * FROM orders o INNER JOIN items ON o.item_id = items.id WHERE (items.id > ? OR (items.id = ? AND o.id > ?)) ORDER BY items.id, o.id [3 3 9]",
+		"true",
+		"true",
+		"true",
+		"0 <nil>",
+		"cursor key of 0 bytes is shorter than 16 bytes",
+		"cursor key of 15 bytes is shorter than 16 bytes",
+	)
+}
+
+func TestGenerated_Statements(t *testing.T) {
+	out := runGenerated(t, sqlModelsFixture, "gorm", `
+	query, args, err := m.NewInsertBuilder(m.Item_.TableName).
+		WithDialect(m.Postgres).
+		Columns(m.Item_.Name, m.Item_.Price, m.Item_.CreatedAt).
+		Values("a", 1.5, clause.Expr{SQL: "CURRENT_TIMESTAMP"}).
+		Values("b", 2.5, clause.Expr{SQL: "CURRENT_TIMESTAMP"}).
+		Build()
+	fmt.Println(query, args, err)
+
+	_, _, err = m.NewInsertBuilder(m.Item_.TableName).Columns(m.Item_.Name, m.Item_.Price).Values("a").Build()
+	fmt.Println(err)
+
+	query, args, err = m.NewUpdateBuilder(m.Item_.TableName).
+		WithDialect(m.SQLServer).
+		Set(m.Item_.Name, "renamed").
+		Incr(m.Item_.Price, -1).
+		SetExpr(m.Item_.Active, clause.Expr{SQL: "NOT ?", Vars: []any{m.Item_.Active}}).
+		Where(m.Item_.ID.Equal(7)).
+		Build()
+	fmt.Println(query, args, err)
+
+	_, _, err = m.NewUpdateBuilder(m.Item_.TableName).Set(m.Item_.Name, "x").Build()
+	fmt.Println(errors.Is(err, m.ErrMissingWhereClause), err)
+	query, args, err = m.NewUpdateBuilder(m.Item_.TableName).Set(m.Item_.Name, "x").Force().Build()
+	fmt.Println(query, args, err)
+
+	query, args, err = m.NewDeleteBuilder(m.Item_.TableName).
+		Where(m.Item_.Active.IsFalse(), m.Or(m.Item_.Price.Lt(1), m.Item_.Name.Equal(""))).
+		Build()
+	fmt.Println(query, args, err)
+
+	_, _, err = m.NewDeleteBuilder(m.Item_.TableName).Build()
+	fmt.Println(errors.Is(err, m.ErrMissingWhereClause), err)
+	query, _, err = m.NewDeleteBuilder(m.Item_.TableName).WithDialect(m.MySQL).Force().Build()
+	fmt.Println(query, err)
+
+	_, _, err = m.NewDeleteBuilder(m.Item_.TableName).Where(m.And()).Build()
+	fmt.Println(errors.Is(err, m.ErrMissingWhereClause), err)
+	_, _, err = m.NewUpdateBuilder(m.Item_.TableName).Set(m.Item_.Name, "x").Where(m.Or(m.And()), clause.Expr{}).Build()
+	fmt.Println(errors.Is(err, m.ErrMissingWhereClause), err)
+	query, args, err = m.NewDeleteBuilder(m.Item_.TableName).Where(m.And(), m.Item_.ID.Equal(3), m.Or()).Build()
+	fmt.Println(query, args, err)
+	query, _ = m.NewQueryBuilder(m.Item_.TableName).Where(m.And()).Having(m.Or()).Build()
+	fmt.Println(query)
+`)
+	assertLines(t, out,
+		`INSERT INTO "items" ("name", "price", "created_at") VALUES ($1, $2, CURRENT_TIMESTAMP), ($3, $4, CURRENT_TIMESTAMP) [a 1.5 b 2.5] <nil>`,
+		"insert into items: row 1 has 1 values for 2 columns",
+		"UPDATE [items] SET [name] = @p1, [price] = [price] + @p2, [active] = NOT [active] WHERE [id] = @p3 [renamed -1 7] <nil>",
+		"true update items: missing WHERE clause",
+		"UPDATE items SET name = ? [x] <nil>",
+		"DELETE FROM items WHERE active = ? AND (price < ? OR name = ?) [false 1 ] <nil>",
+		"true delete from items: missing WHERE clause",
+		"DELETE FROM `items` <nil>",
+		"true delete from items: missing WHERE clause",
+		"true update items: missing WHERE clause",
+		"DELETE FROM items WHERE id = ? [3] <nil>",
+		"SELECT * FROM items",
+	)
+}
+
+func TestGenerated_Upsert(t *testing.T) {
+	out := runGenerated(t, sqlModelsFixture, "gorm", `
+	insert := func(d m.Dialect, oc clause.OnConflict) {
+		query, args, err := m.NewInsertBuilder(m.Item_.TableName).
+			WithDialect(d).
+			Columns(m.Item_.ID, m.Item_.Name, m.Item_.Price).
+			Values(1, "a", 2.5).
+			OnConflict(oc).
+			Build()
+		fmt.Println(query, args, err)
+	}
+	insert(m.Postgres, m.OnConflict(m.Item_.ID).DoUpdate(m.Item_.Name, m.Item_.Price))
+	insert(m.SQLite, m.OnConflict(m.Item_.ID).DoNothing())
+	insert(m.GenericSQL, m.OnConflict(m.Item_.ID).DoUpdateAll())
+	insert(m.GenericSQL, m.OnConflict(m.Item_.ID).DoUpdateSet(
+		clause.Assignment{Column: clause.Column{Name: "price"}, Value: 0},
+	))
+	insert(m.MySQL, m.OnConflict(m.Item_.ID).DoUpdate(m.Item_.Name))
+	insert(m.MySQL, m.OnConflict(m.Item_.ID).DoNothing())
+	insert(m.SQLServer, m.OnConflict(m.Item_.ID).DoNothing())
+
+	type item struct {
+		ID    int
+		Name  string
+		Price float64
+	}
+	rows := []item{{1, "a", 2.5}}
+	stmt := dryRunDB().Table(m.Item_.TableName).
+		Clauses(m.OnConflict(m.Item_.ID).DoUpdate(m.Item_.Name)).
+		Create(&rows).Statement
+	fmt.Println(stmt.SQL.String())
+`)
+	assertLines(t, out,
+		`INSERT INTO "items" ("id", "name", "price") VALUES ($1, $2, $3) ON CONFLICT ("id") DO UPDATE SET "name" = "excluded"."name", "price" = "excluded"."price" [1 a 2.5] <nil>`,
+		"INSERT INTO `items` (`id`, `name`, `price`) VALUES (?, ?, ?) ON CONFLICT (`id`) DO NOTHING [1 a 2.5] <nil>",
+		"INSERT INTO items (id, name, price) VALUES (?, ?, ?) ON CONFLICT (id) DO UPDATE SET name = excluded.name, price = excluded.price [1 a 2.5] <nil>",
+		"INSERT INTO items (id, name, price) VALUES (?, ?, ?) ON CONFLICT (id) DO UPDATE SET price = ? [1 a 2.5 0] <nil>",
+		"INSERT INTO `items` (`id`, `name`, `price`) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`) [1 a 2.5] <nil>",
+		"INSERT INTO `items` (`id`, `name`, `price`) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE `id` = `id` [1 a 2.5] <nil>",
+		" [] insert into items: upsert is not supported by sqlserver",
+		`INSERT INTO "items" ("name","price","id") VALUES (?,?,?) ON CONFLICT ("id") DO UPDATE SET "name"="excluded"."name"`,
+	)
+}
+
+func TestGenerated_Functions(t *testing.T) {
+	out := runGenerated(t, sqlModelsFixture, "gorm", `
+	total := m.Order_.ID.Count()
+	query, args := m.NewQueryBuilder(m.Order_.TableName).
+		WithDialect(m.Postgres).
+		Select(m.Order_.ItemID, total.As("total"), m.Order_.Amount.Sum().Coalesce(0).As("amount"), m.Order_.Amount.Avg()).
+		GroupBy(m.Order_.ItemID).
+		Having(total.Gt(5), m.Order_.Amount.Max().Lte(100)).
+		OrderByExpr(total.Desc()).
+		OrderBy(m.Order_.ItemID.Asc()).
+		Build()
+	fmt.Println(query, args)
+
+	query, args = m.NewQueryBuilder(m.Item_.TableName).
+		Select(m.Item_.CreatedAt.DateTrunc("day"), m.Item_.Name.CountDistinct(), m.CountAll()).
+		Where(m.Item_.Name.Lower().Equal("abc"), m.Item_.Name.Upper().NotEqual("X")).
+		GroupBy(m.Item_.CreatedAt.DateTrunc("day")).
+		Build()
+	fmt.Println(query, args)
+
+	byMonth := m.NewQueryBuilder(m.Item_.TableName).
+		WithDialect(m.Postgres).
+		Select(m.Item_.CreatedAt.DateTrunc("month"), m.CountAll()).
+		Where(m.Item_.Active.IsTrue()).
+		GroupBy(m.Item_.CreatedAt.DateTrunc("month"))
+	query, args = byMonth.Build()
+	fmt.Println(query, args)
+
+	invalid := m.NewQueryBuilder(m.Item_.TableName).Select(m.Item_.CreatedAt.DateTrunc("day'); DROP TABLE items; --"))
+	invalid.Build()
+	fmt.Println(invalid.Err())
+
+	query, _ = m.NewQueryBuilder(m.Order_.TableName).
+		Select(m.Item_.Price.Min(), m.Item_.Price.Coalesce(1.5)).
+		InnerJoin(m.Item_.TableName, m.On(m.Order_.ItemID, m.Item_.ID)).
+		Build()
+	fmt.Println(query)
+
+	fmt.Println(gormSQL(m.Order_.TableName, func(db *gorm.DB) *gorm.DB {
+		return db.Select("?, ?", m.Order_.ItemID, total.As("total")).
+			Group(m.Order_.ItemID.String()).
+			Having(total.Gte(2)).
+			Order(total.Desc())
+	}))
+	fmt.Println(total, m.Order_.Amount.Sum().Coalesce(0).String())
+	fmt.Println(m.Item_.Name.Coalesce("it's").String(), m.Item_.Active.Coalesce(true))
+	m.DefaultDialect = m.MySQL
+	fmt.Println(m.Item_.Name.Coalesce("a\\'b"))
+`)
+	assertLines(t, out,
+		`SELECT "item_id", COUNT("id") AS "total", COALESCE(SUM("amount"), $1) AS "amount", AVG("amount") FROM "orders" GROUP BY "item_id" HAVING COUNT("id") > $2 AND MAX("amount") <= $3 ORDER BY COUNT("id") DESC, "item_id" [0 5 100]`,
+		"SELECT DATE_TRUNC('day', created_at), COUNT(DISTINCT name), COUNT(*) FROM items WHERE LOWER(name) = ? AND UPPER(name) <> ? GROUP BY DATE_TRUNC('day', created_at) [abc X]",
+		`SELECT DATE_TRUNC('month', "created_at"), COUNT(*) FROM "items" WHERE "active" = $1 GROUP BY DATE_TRUNC('month', "created_at") [true]`,
+		`DATE_TRUNC unit "day'); DROP TABLE items; --" is not one of microseconds, milliseconds, second, minute, hour, day, week, month, quarter, year, decade, century, millennium`,
+		"SELECT MIN(items.price), COALESCE(items.price, ?) FROM orders INNER JOIN items ON orders.item_id = items.id",
+		`SELECT "item_id", COUNT("id") AS "total" FROM "orders" GROUP BY "item_id" HAVING COUNT("id") >= ? ORDER BY COUNT("id") DESC [2]`,
+		"COUNT(id) COALESCE(SUM(amount), 0)",
+		"COALESCE(name, 'it''s') COALESCE(active, true)",
+		"COALESCE(`name`, 'a\\\\''b')",
+	)
+}
+
+func TestGenerated_Subqueries(t *testing.T) {
+	out := runGenerated(t, sqlModelsFixture, "gorm", `
+	bigOrders := m.NewQueryBuilder(m.Order_.TableName).
+		Select(m.Order_.ItemID).
+		Where(m.Order_.Amount.Gt(10))
+	query, args := m.NewQueryBuilder(m.Item_.TableName).
+		WithDialect(m.Postgres).
+		Where(m.Item_.Active.IsTrue(), m.Item_.ID.InQuery(bigOrders), m.Item_.Price.Lt(5)).
+		Build()
+	fmt.Println(query, args)
+
+	ordered := m.NewQueryBuilder(m.Order_.TableName).Where(m.Order_.ItemID.EqField(m.Item_.ID))
+	query, args = m.NewQueryBuilder(m.Item_.TableName).
+		WithDialect(m.SQLServer).
+		Where(m.Exists(ordered), m.NotExists(m.NewQueryBuilder("returns").Where(m.Item_.Name.Equal("x")))).
+		Build()
+	fmt.Println(query, args)
+
+	query, args = m.NewQueryBuilder(m.Item_.TableName).
+		WithDialect(m.Postgres).
+		Select(m.Item_.ID).
+		Where(m.Item_.Price.Gt(1)).
+		Union(m.NewQueryBuilder(m.Order_.TableName).Select(m.Order_.ItemID).Where(m.Order_.Amount.Gt(2))).
+		UnionAll(m.NewQueryBuilder("archive").Select(m.Item_.ID).OrderBy(m.Item_.ID.Asc()).Limit(3)).
+		Intersect(m.NewQueryBuilder("active").Select(m.Item_.ID)).
+		OrderBy(m.Item_.ID.Desc()).
+		Limit(10).
+		Build()
+	fmt.Println(query, args)
+
+	query, _ = m.NewQueryBuilder("a").Union(m.NewQueryBuilder("b")).WithDialect(m.SQLServer).Limit(5).Build()
+	fmt.Println(query)
+
+	fmt.Println(gormSQL(m.Item_.TableName, func(db *gorm.DB) *gorm.DB {
+		return db.Where(m.Item_.Name.Equal("n")).Where(m.Item_.ID.InQuery(bigOrders))
+	}))
+`)
+	assertLines(t, out,
+		`SELECT * FROM "items" WHERE "active" = $1 AND "id" IN (SELECT "item_id" FROM "orders" WHERE "amount" > $2) AND "price" < $3 [true 10 5]`,
+		"SELECT * FROM [items] WHERE EXISTS (SELECT * FROM [orders] WHERE [orders].[item_id] = [items].[id]) AND NOT EXISTS (SELECT * FROM [returns] WHERE [name] = @p1) [x]",
+		`SELECT "id" FROM "items" WHERE "price" > $1 UNION SELECT "item_id" FROM "orders" WHERE "amount" > $2 UNION ALL (SELECT "id" FROM "archive" ORDER BY "id" LIMIT 3) INTERSECT SELECT "id" FROM "active" ORDER BY "id" DESC LIMIT 10 [1 2]`,
+		"SELECT TOP (5) * FROM (SELECT * FROM [a] UNION SELECT * FROM [b]) AS compound",
+		`SELECT * FROM "items" WHERE "name" = ? AND "id" IN (SELECT item_id FROM orders WHERE amount > ?) [n 10]`,
+	)
+}
+
+func TestGenerated_CTEsAndWindows(t *testing.T) {
+	out := runGenerated(t, sqlModelsFixture, "gorm", `
+	ranked := m.NewQueryBuilder(m.Item_.TableName).
+		Select(
+			m.Item_.ID,
+			m.RowNumber().PartitionBy(m.Item_.Active).OrderBy(m.Item_.CreatedAt.Desc(), m.Item_.ID.Asc()).As("position"),
+			m.Item_.Price.Sum().Over().PartitionBy(m.Item_.Active).As("total"),
+			m.Rank().OrderBy(m.Item_.Price.Desc()).As("price_rank"),
+			m.DenseRank().As("dense"),
+		).
+		Where(m.Item_.Price.Gt(1))
+	query, args := m.NewQueryBuilder("ranked").
+		WithDialect(m.Postgres).
+		With("ranked", ranked).
+		Where(clause.Expr{SQL: "? <= ?", Vars: []any{clause.Column{Name: "position"}, 3}}).
+		Build()
+	fmt.Println(query, args)
+
+	tree := m.Item_.As("tree")
+	children := m.Item_.As("c")
+	for _, d := range []m.Dialect{m.SQLite, m.SQLServer} {
+		query, args = m.NewQueryBuilder("tree").
+			WithDialect(d).
+			WithRecursive("tree", m.NewQueryBuilder(m.Item_.TableName).
+				Select(m.Item_.ID).
+				Where(m.Item_.ID.Equal(1)).
+				UnionAll(m.NewQueryBuilder(m.Item_.TableName+" c").
+					Select(children.ID).
+					InnerJoin("tree", children.Price.EqField(tree.ID)))).
+			With("other", m.NewQueryBuilder(m.Order_.TableName).Where(m.Order_.Amount.Lt(9))).
+			Build()
+		fmt.Println(query, args)
+	}
+
+	query, _ = m.NewQueryBuilder(m.Order_.TableName+" o").
+		Select(
+			m.Order_.ID,
+			m.RowNumber().PartitionBy(m.Item_.ID).OrderBy(m.Order_.Amount.WithDefaultOwner().Desc(), m.Item_.CreatedAt.WithDefaultOwner().Asc()).As("position"),
+		).
+		InnerJoin(m.Item_.TableName, m.On(m.Order_.ItemID, m.Item_.ID)).
+		Build()
+	fmt.Println(query)
+`)
+	assertLines(t, out,
+		`WITH "ranked" AS (SELECT "id", ROW_NUMBER() OVER (PARTITION BY "active" ORDER BY "created_at" DESC, "id") AS "position", SUM("price") OVER (PARTITION BY "active") AS "total", RANK() OVER (ORDER BY "price" DESC) AS "price_rank", DENSE_RANK() OVER () AS "dense" FROM "items" WHERE "price" > $1) SELECT * FROM "ranked" WHERE "position" <= $2 [1 3]`,
+		"WITH RECURSIVE `tree` AS (SELECT `id` FROM `items` WHERE `id` = ? UNION ALL SELECT `c`.`id` FROM `items` `c` INNER JOIN `tree` ON `c`.`price` = `tree`.`id`), `other` AS (SELECT * FROM `orders` WHERE `amount` < ?) SELECT * FROM `tree` [1 9]",
+		"WITH [tree] AS (SELECT [id] FROM [items] WHERE [id] = @p1 UNION ALL SELECT [c].[id] FROM [items] [c] INNER JOIN [tree] ON [c].[price] = [tree].[id]), [other] AS (SELECT * FROM [orders] WHERE [amount] < @p2) SELECT * FROM [tree] [1 9]",
+		"SELECT o.id, ROW_NUMBER() OVER (PARTITION BY items.id ORDER BY o.amount DESC, items.created_at) AS position FROM orders o INNER JOIN items ON o.item_id = items.id",
+	)
+}
+
+func TestGenerated_MongoRanges(t *testing.T) {
+	models := `package models
+
+import "go.mongodb.org/mongo-driver/v2/bson"
+
+type Event struct {
+	ID    bson.ObjectID ` + "`bson:\"_id\"`" + `
+	Name  string        ` + "`bson:\"name\"`" + `
+	Score int           ` + "`bson:\"score\"`" + `
+}
+`
+	out := runGenerated(t, models, "bson", `
+	for _, doc := range []bson.D{
+		m.Event_.ID.MgoGt(bson.ObjectID{1}),
+		m.Event_.Name.MgoGte("a"),
+		m.Event_.Name.MgoLt("n"),
+		m.Event_.Score.MgoLte(3),
+	} {
+		out, err := bson.MarshalExtJSON(doc, false, false)
+		fmt.Println(string(out), err)
+	}
+`)
+	assertLines(t, out,
+		`{"_id":{"$gt":{"$oid":"010000000000000000000000"}}} <nil>`,
+		`{"name":{"$gte":"a"}} <nil>`,
+		`{"name":{"$lt":"n"}} <nil>`,
+		`{"score":{"$lte":3}} <nil>`,
+	)
+}
+
+func TestGenerated_MongoUpdate(t *testing.T) {
+	out := runGenerated(t, mongoModelsFixture, "json,bson", `
+	for _, doc := range []bson.D{
+		m.Scenario_.Score.MgoGt(1),
+		m.MgoUpdate(
+			m.Scenario_.Name.MgoSet("a"),
+			m.Scenario_.Score.MgoInc(2),
+			m.Scenario_.Tags.MgoUnset(),
+			m.Scenario_.Score.MgoMax(9),
+			m.Scenario_.UpdatedAt.MgoCurrentDate(),
+			m.Scenario_.ID.MgoSet("x"),
+			m.Scenario_.Score.MgoMin(1),
+		),
+		m.MgoUpdate(
+			m.Scenario_.Score.MgoMul(3),
+			m.Scenario_.Tags.MgoPush("a"),
+			m.Scenario_.Tags.MgoAddToSet("b", "c"),
+		),
+		m.MgoUpdate(m.Scenario_.Tags.MgoPull(bson.D{{Key: "$in", Value: bson.A{"a", "b"}}})),
+		m.MgoUpdate(),
+	} {
+		out, err := bson.MarshalExtJSON(doc, false, false)
+		fmt.Println(string(out), err)
+	}
+`)
+	assertLines(t, out,
+		`{"score":{"$gt":1}} <nil>`,
+		`{"$set":{"name":"a","_id":"x"},"$inc":{"score":2},"$unset":{"tags":""},"$max":{"score":9},"$currentDate":{"updated_at":true},"$min":{"score":1}} <nil>`,
+		`{"$mul":{"score":3},"$push":{"tags":"a"},"$addToSet":{"tags":{"$each":["b","c"]}}} <nil>`,
+		`{"$pull":{"tags":{"$in":["a","b"]}}} <nil>`,
+		`{} <nil>`,
+	)
+}
+
+func TestGenerated_MongoPipeline(t *testing.T) {
+	out := runGenerated(t, mongoModelsFixture, "json,bson", `
+	pipeline := m.NewMgoPipeline().
+		Match(m.Scenario_.Score.MgoGt(1)).
+		Match(m.Scenario_.Score.MgoGt(1), m.Scenario_.Name.MgoNe("x")).
+		Lookup(m.Owner_.TableName, m.Scenario_.OwnerID, m.Owner_.ID, "owners").
+		Unwind(m.MgoName("owners")).
+		UnwindPreserveEmpty(m.Scenario_.Tags).
+		Group(m.Scenario_.Name).Count("total").Sum("score", m.Scenario_.Score).Max("last", m.Scenario_.UpdatedAt).
+		Group(m.Scenario_.Name, m.Scenario_.Owner.Name).Push("tags", m.Scenario_.Tags).
+		Group().Avg("avg", m.MgoName("score")).
+		Project(m.Scenario_.Name, m.Scenario_.Owner.Name).
+		Exclude(m.Scenario_.ID).
+		Sort(m.Scenario_.Name.MgoAsc(), bson.D{{Key: "total", Value: -1}}).
+		Skip(5).
+		Limit(10).
+		Build()
+	for _, stage := range pipeline {
+		out, err := bson.MarshalExtJSON(stage, false, false)
+		fmt.Println(string(out), err)
+	}
+`)
+	assertLines(t, out,
+		`{"$match":{"score":{"$gt":1}}} <nil>`,
+		`{"$match":{"$and":[{"score":{"$gt":1}},{"name":{"$ne":"x"}}]}} <nil>`,
+		`{"$lookup":{"from":"owners","localField":"owner_id","foreignField":"_id","as":"owners"}} <nil>`,
+		`{"$unwind":"$owners"} <nil>`,
+		`{"$unwind":{"path":"$tags","preserveNullAndEmptyArrays":true}} <nil>`,
+		`{"$group":{"_id":"$name","total":{"$sum":1},"score":{"$sum":"$score"},"last":{"$max":"$updated_at"}}} <nil>`,
+		`{"$group":{"_id":{"name":"$name","owner_name":"$owner.name"},"tags":{"$push":"$tags"}}} <nil>`,
+		`{"$group":{"_id":null,"avg":{"$avg":"$score"}}} <nil>`,
+		`{"$project":{"name":1,"owner.name":1}} <nil>`,
+		`{"$project":{"_id":0}} <nil>`,
+		`{"$sort":{"name":1,"total":-1}} <nil>`,
+		`{"$skip":5} <nil>`,
+		`{"$limit":10} <nil>`,
+	)
+}
+
+func TestGenerated_MongoArrays(t *testing.T) {
+	out := runGenerated(t, mongoModelsFixture, "json,bson", `
+	items := m.Scenario_.Items
+	for _, doc := range []bson.D{
+		m.Scenario_.Tags.MgoAll("a", "b"),
+		m.Scenario_.Tags.MgoEq("x"),
+		m.Scenario_.Tags.MgoNe("y"),
+		m.Scenario_.Tags.MgoIn("a", "b"),
+		m.Scenario_.Tags.MgoNin("c"),
+		m.Scenario_.Tags.MgoEq([]string{"a", "b"}),
+		m.Scenario_.Tags.MgoSize(2),
+		items.MgoElemMatch(items.Name.MgoEq("a"), items.Qty.MgoGt(1)),
+		m.Scenario_.Tags.MgoElemMatch(bson.D{{Key: "$gte", Value: "a"}}, bson.D{{Key: "$lt", Value: "c"}}),
+		m.Scenario_.Score.MgoType("int", "long"),
+		m.Scenario_.Name.MgoType("string"),
+		items.MgoType("array"),
+		m.Scenario_.Score.MgoMod(4, 0),
+		m.Scenario_.Tags.At(0).MgoEq("first"),
+		m.MgoUpdate(
+			m.Scenario_.Tags.Positional().MgoSet("new"),
+			items.AllPositional().Qty.MgoInc(1),
+			items.At(2).Owners.Positional().Name.MgoSet("x"),
+			items.Positional().MgoUnset(),
+		),
+	} {
+		out, err := bson.MarshalExtJSON(doc, false, false)
+		fmt.Println(string(out), err)
+	}
+	fmt.Println(items.Name.FieldName, items.Name.BSON, items.At(1).Owners.ID.BSON)
+	aliased := m.Scenario_.As("s")
+	fmt.Println(aliased.Owner.String(), aliased.Owner.Name.String(), aliased.Items.At(1).Owners.ID.String())
+`)
+	assertLines(t, out,
+		`{"tags":{"$all":["a","b"]}} <nil>`,
+		`{"tags":{"$eq":"x"}} <nil>`,
+		`{"tags":{"$ne":"y"}} <nil>`,
+		`{"tags":{"$in":["a","b"]}} <nil>`,
+		`{"tags":{"$nin":["c"]}} <nil>`,
+		`{"tags":{"$eq":["a","b"]}} <nil>`,
+		`{"tags":{"$size":2}} <nil>`,
+		`{"items":{"$elemMatch":{"name":{"$eq":"a"},"qty":{"$gt":1}}}} <nil>`,
+		`{"tags":{"$elemMatch":{"$gte":"a","$lt":"c"}}} <nil>`,
+		`{"score":{"$type":["int","long"]}} <nil>`,
+		`{"name":{"$type":"string"}} <nil>`,
+		`{"items":{"$type":"array"}} <nil>`,
+		`{"score":{"$mod":[4,0]}} <nil>`,
+		`{"tags.0":{"$eq":"first"}} <nil>`,
+		`{"$set":{"tags.$":"new","items.2.owners.$.name":"x"},"$inc":{"items.$[].qty":1},"$unset":{"items.$":""}} <nil>`,
+		`items.name items.name items.1.owners._id`,
+		`s.owner s.owner.name s.items.1.owners.id`,
+	)
+}
+
+func TestGenerated_MongoQuery(t *testing.T) {
+	out := runGeneratedWith(t, mongoModelsFixture, "json,bson", `
+func printJSON(v any) {
+	if v == nil {
+		fmt.Println("<nil>")
+		return
+	}
+	out, err := bson.MarshalExtJSON(v, false, false)
+	fmt.Println(string(out), err)
+}
+
+func printInt(n *int64) {
+	if n == nil {
+		fmt.Println("<nil>")
+		return
+	}
+	fmt.Println(*n)
+}
+`, `
+	base := m.NewMgoQuery(m.Scenario_.Score.MgoGt(1)).Sort(m.Scenario_.Score.MgoDesc())
+	q := base.
+		Where(m.Scenario_.Name.MgoNe("x")).
+		Sort(m.Scenario_.Name.MgoAsc(), m.Scenario_.ID.MgoAsc()).
+		Include(m.Scenario_.Name, m.Scenario_.Owner.Name).
+		Exclude(m.Scenario_.ID).
+		Skip(20).
+		Limit(10)
+	printJSON(m.NewMgoQuery().Filter())
+	printJSON(base.Filter())
+	printJSON(q.Filter())
+
+	var find options.FindOptions
+	for _, set := range q.FindOptions().List() {
+		_ = set(&find)
+	}
+	printJSON(find.Sort)
+	printJSON(find.Projection)
+	printInt(find.Skip)
+	printInt(find.Limit)
+
+	var findOne options.FindOneOptions
+	for _, set := range q.FindOneOptions().List() {
+		_ = set(&findOne)
+	}
+	printJSON(findOne.Sort)
+	printInt(findOne.Skip)
+
+	var empty options.FindOptions
+	for _, set := range base.Where().FindOptions().List() {
+		_ = set(&empty)
+	}
+	printJSON(empty.Sort)
+	printJSON(empty.Projection)
+	printInt(empty.Limit)
+`)
+	assertLines(t, out,
+		`{} <nil>`,
+		`{"score":{"$gt":1}} <nil>`,
+		`{"$and":[{"score":{"$gt":1}},{"name":{"$ne":"x"}}]} <nil>`,
+		`{"score":-1,"name":1,"_id":1} <nil>`,
+		`{"name":1,"owner.name":1,"_id":0} <nil>`,
+		`20`,
+		`10`,
+		`{"score":-1,"name":1,"_id":1} <nil>`,
+		`20`,
+		`{"score":-1} <nil>`,
+		`<nil>`,
+		`<nil>`,
+	)
+}
+
+func TestGenerated_MongoIndexes(t *testing.T) {
+	models := strings.Replace(mongoModelsFixture, "type Scenario struct {", `//metamodel:index=Name, -Score unique name=by_name partial={"score": {"$gt": 0.5}, "name": {"$exists": true}}
+//metamodel:index=Owner.Name sparse
+type Scenario struct {`, 1)
+	models = strings.Replace(models, "`json:\"updatedAt\" bson:\"updated_at\"`", "`json:\"updatedAt\" bson:\"updated_at\" metamodel:\"index,desc,ttl=1h\"`", 1)
+	models = strings.Replace(models, "type Item struct {", "//metamodel:index=Qty,Name\ntype Item struct {", 1)
+	out := runGeneratedWith(t, models, "json,bson", `
+type fakeIndexView struct {
+	collection string
+	fail       bool
+}
+
+func (v fakeIndexView) CreateMany(ctx context.Context, models []mongo.IndexModel, opts ...options.Lister[options.CreateIndexesOptions]) ([]string, error) {
+	if v.fail {
+		return nil, errors.New("boom")
+	}
+	for _, model := range models {
+		keys, _ := bson.MarshalExtJSON(model.Keys, false, false)
+		var idx options.IndexOptions
+		if model.Options != nil {
+			for _, set := range model.Options.List() {
+				_ = set(&idx)
+			}
+		}
+		line := v.collection + " " + string(keys)
+		if idx.Unique != nil {
+			line += fmt.Sprint(" unique=", *idx.Unique)
+		}
+		if idx.Sparse != nil {
+			line += fmt.Sprint(" sparse=", *idx.Sparse)
+		}
+		if idx.ExpireAfterSeconds != nil {
+			line += fmt.Sprint(" ttl=", *idx.ExpireAfterSeconds)
+		}
+		if idx.Name != nil {
+			line += " name=" + *idx.Name
+		}
+		if idx.PartialFilterExpression != nil {
+			partial, _ := bson.MarshalExtJSON(idx.PartialFilterExpression, false, false)
+			line += " partial=" + string(partial)
+		}
+		fmt.Println(line)
+	}
+	return nil, nil
+}
+`, `
+	ctx := context.Background()
+	err := m.EnsureIndexesWith(ctx, func(collection string) m.MgoIndexView {
+		return fakeIndexView{collection: collection}
+	})
+	fmt.Println(err)
+	err = m.EnsureIndexesWith(ctx, func(collection string) m.MgoIndexView {
+		return fakeIndexView{collection: collection, fail: true}
+	})
+	fmt.Println(err)
+	fmt.Println(len(m.Scenario_.Indexes()))
+`)
+	assertLines(t, out,
+		`scenarios {"name":1,"score":-1} unique=true name=by_name partial={"score":{"$gt":0.5},"name":{"$exists":true}}`,
+		`scenarios {"owner.name":1} sparse=true`,
+		`scenarios {"updated_at":-1} ttl=3600`,
+		`items {"qty":1,"name":1}`,
+		`<nil>`,
+		`create indexes of scenarios: boom`,
+		`3`,
+	)
+}
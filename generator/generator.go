@@ -2,10 +2,16 @@ package generator
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"go/build"
 	"go/format"
+	"io/fs"
+	"maps"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 	"text/template"
 	"unicode"
@@ -13,23 +19,34 @@ import (
 
 // Config holds the configuration for code generation
 type Config struct {
-	Source      string
+	Source      string // file, package directory or "dir/..." pattern
 	Destination string
 	PackageName string
 	Tag         string
-	TableName   string
+	TableName   string // table of the only struct of the source, or "Struct=table" pairs separated by commas
+	Naming      NamingStrategy
 }
 
 // StructMeta holds metadata for a struct
 type StructMeta struct {
 	StructName string
+	Name       string   // name of the generated metamodel, StructName unless renamed by //metamodel:name
+	TableName  string   // from //metamodel:table or the struct's TableName() method, if any
+	Tags       []string // struct tags the fields were read from, the first one naming the fields
 	Fields     []FieldMeta
+	Indexes    []IndexMeta // MongoDB indexes declared by //metamodel:index or metamodel:"index" tags
 }
 
 // FieldMeta holds metadata for a struct field
 type FieldMeta struct {
-	FieldName string
-	TagName   string
+	FieldName   string
+	TagName     string
+	Type        string            // Go type as written in the source, e.g. *time.Time
+	Kind        string            // generated field type, one of the Kind constants; empty means KindField
+	TypeArg     string            // type argument of the generated Field[T]; empty means any
+	TypeImports map[string]string // package name -> import path used by TypeArg
+	Fields      []FieldMeta       // fields of a nested document, TagName holding their dotted path
+	Names       map[string]string // tag -> name, when several tags are generated together
 }
 
 func toSnakeCase(s string) string {
@@ -47,57 +64,235 @@ func toSnakeCase(s string) string {
 	return b.String()
 }
 
-// Generate generates metamodel code for the given configuration
+// sourceUnit is a parsed file or package together with the file its metamodel is written to
+type sourceUnit struct {
+	Source      string
+	PackageName string
+	Structs     []StructMeta
+	DestPath    string
+}
+
+// Generate generates metamodel code for the given configuration.
+// Source may be a single file, a package directory or a "dir/..." pattern
+// matching every package below dir.
 func Generate(cfg Config) error {
-	// Parse the source file
+	units, err := loadSources(cfg)
+	if err != nil {
+		return err
+	}
+	if len(units) == 0 {
+		return fmt.Errorf("no structs found in %s", cfg.Source)
+	}
+	if cfg.Destination != "" && !isDirDestination(cfg.Destination) && len(units) > 1 {
+		return fmt.Errorf("destination %s is a file but %s matches %d packages", cfg.Destination, cfg.Source, len(units))
+	}
+
+	// Every destination directory receives the shared files exactly once, so all
+	// units written there must agree on the package and must not redeclare a struct.
+	destPkgs := make(map[string]string)
+	destTags := make(map[string]map[string]bool)
+	var destDirs []string
+	declared := make(map[string]string)
+	written := make(map[string]string)
+	for i := range units {
+		unit := &units[i]
+		if cfg.PackageName != "" {
+			unit.PackageName = cfg.PackageName + "_"
+		}
+		unit.DestPath, err = destinationPath(cfg.Destination, unit.Source)
+		if err != nil {
+			return err
+		}
+		// packages sharing a directory name would write the same file
+		if other, ok := written[unit.DestPath]; ok {
+			return fmt.Errorf("%s and %s both generate %s", other, unit.Source, unit.DestPath)
+		}
+		written[unit.DestPath] = unit.Source
+		destDir := filepath.Dir(unit.DestPath)
+		if pkg, ok := destPkgs[destDir]; !ok {
+			destPkgs[destDir] = unit.PackageName
+			destTags[destDir] = make(map[string]bool)
+			destDirs = append(destDirs, destDir)
+		} else if pkg != unit.PackageName {
+			return fmt.Errorf("conflicting package names %s and %s in %s", pkg, unit.PackageName, destDir)
+		}
+		for _, st := range unit.Structs {
+			key := filepath.Join(destDir, st.Name)
+			if other, ok := declared[key]; ok {
+				return fmt.Errorf("metamodel %s_ is declared in both %s and %s", st.Name, other, unit.Source)
+			}
+			declared[key] = unit.Source
+			for _, tag := range st.Tags {
+				destTags[destDir][tag] = true
+			}
+			if len(st.Indexes) > 0 {
+				// the indexes are registered with the MongoDB helpers
+				destTags[destDir]["bson"] = true
+			}
+		}
+	}
+
+	tableNames, err := parseTableNames(cfg.TableName, units)
+	if err != nil {
+		return err
+	}
+	for _, unit := range units {
+		if err := generateMetamodelFile(cfg, unit, tableNames); err != nil {
+			return err
+		}
+	}
+	for _, destDir := range destDirs {
+		for _, f := range sharedFiles {
+			if f.tag != "" && !destTags[destDir][f.tag] {
+				continue
+			}
+			if err := writeSharedFile(f.tmpl, f.name, destPkgs[destDir], destDir); err != nil {
+				return fmt.Errorf("failed to write %s: %w", f.name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// loadSources parses cfg.Source into one unit per file or package.
+func loadSources(cfg Config) ([]sourceUnit, error) {
+	if root, ok := strings.CutSuffix(cfg.Source, "..."); ok {
+		return loadPackagePattern(strings.TrimSuffix(root, "/"), cfg.Tag)
+	}
+	info, err := os.Stat(cfg.Source)
+	if err == nil && info.IsDir() {
+		structs, pkgName, err := parsePackage(cfg.Source, cfg.Tag)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse source package: %w", err)
+		}
+		if len(structs) == 0 {
+			return nil, nil
+		}
+		return []sourceUnit{{Source: cfg.Source, PackageName: pkgName, Structs: structs}}, nil
+	}
 	structs, pkgName, err := parseFile(cfg.Source, cfg.Tag)
 	if err != nil {
-		return fmt.Errorf("failed to parse source file: %w", err)
+		return nil, fmt.Errorf("failed to parse source file: %w", err)
 	}
 	if len(structs) == 0 {
-		return fmt.Errorf("no structs found in %s", cfg.Source)
+		return nil, nil
 	}
-	if cfg.PackageName != "" {
-		pkgName = cfg.PackageName + "_"
-	}
-	destPath := cfg.Destination
-	if destPath == "" {
-		ext := filepath.Ext(cfg.Source)
-		destPath = strings.TrimSuffix(cfg.Source, ext) + "_metamodel.go"
-	} else if strings.HasSuffix(destPath, "/") || strings.HasSuffix(destPath, string(filepath.Separator)) {
-		if err := os.MkdirAll(destPath, 0755); err != nil {
-			return fmt.Errorf("failed to create destination directory: %w", err)
-		}
-		sourceBase := filepath.Base(cfg.Source)
-		ext := filepath.Ext(sourceBase)
-		filename := strings.TrimSuffix(sourceBase, ext) + "_metamodel.go"
-		destPath = filepath.Join(destPath, filename)
-	} else {
-		destDir := filepath.Dir(destPath)
-		if destDir != "." && destDir != "" {
-			if err := os.MkdirAll(destDir, 0755); err != nil {
-				return fmt.Errorf("failed to create destination directory: %w", err)
+	return []sourceUnit{{Source: cfg.Source, PackageName: pkgName, Structs: structs}}, nil
+}
+
+// loadPackagePattern parses every package below root, skipping the directories
+// the go tool ignores (vendor, testdata, hidden and underscore-prefixed ones).
+func loadPackagePattern(root, tag string) ([]sourceUnit, error) {
+	if root == "" {
+		root = "."
+	}
+	var units []sourceUnit
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		name := d.Name()
+		if path != root && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+			return filepath.SkipDir
+		}
+		structs, pkgName, err := parsePackage(path, tag)
+		if err != nil {
+			var noGo *build.NoGoError
+			if errors.As(err, &noGo) {
+				return nil
 			}
+			return fmt.Errorf("failed to parse source package: %w", err)
 		}
+		if len(structs) > 0 {
+			units = append(units, sourceUnit{Source: path, PackageName: pkgName, Structs: structs})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return units, nil
+}
+
+// isDirDestination reports whether dest names a directory rather than a file.
+func isDirDestination(dest string) bool {
+	return strings.HasSuffix(dest, "/") || strings.HasSuffix(dest, string(filepath.Separator))
+}
+
+// destinationPath computes the output file for source and creates its directory.
+// Files map to <name>_metamodel.go, packages to <package dir>_metamodel.go.
+func destinationPath(dest, source string) (string, error) {
+	var dir, base string
+	if info, err := os.Stat(source); err == nil && info.IsDir() {
+		abs, err := filepath.Abs(source)
+		if err != nil {
+			return "", err
+		}
+		dir, base = source, filepath.Base(abs)
+	} else {
+		dir, base = filepath.Dir(source), strings.TrimSuffix(filepath.Base(source), filepath.Ext(source))
 	}
+	filename := base + "_metamodel.go"
 
+	var destPath string
+	switch {
+	case dest == "":
+		return filepath.Join(dir, filename), nil
+	case isDirDestination(dest):
+		destPath = filepath.Join(dest, filename)
+	default:
+		destPath = dest
+	}
+	destDir := filepath.Dir(destPath)
+	if destDir != "." && destDir != "" {
+		if err := os.MkdirAll(destDir, 0755); err != nil {
+			return "", fmt.Errorf("failed to create destination directory: %w", err)
+		}
+	}
+	return destPath, nil
+}
+
+// generateMetamodelFile renders the struct metamodels of a single unit, with
+// the tables named by the -tableName flag.
+func generateMetamodelFile(cfg Config, unit sourceUnit, tableNames map[string]string) error {
 	// Prepare template data
+	imports, err := typeImports(unit.Structs)
+	if err != nil {
+		return err
+	}
 	data := struct {
 		PackageName string
+		Imports     []typeImport
 		Structs     []StructMeta
 	}{
-		PackageName: pkgName,
-		Structs:     structs,
+		PackageName: unit.PackageName,
+		Imports:     imports,
+		Structs:     unit.Structs,
 	}
 	// Execute template
-	tableNameFn := func(structName string) string {
-		if cfg.TableName != "" {
-			return cfg.TableName
+	tableNameFn := func(st StructMeta) string {
+		if st.TableName != "" {
+			return st.TableName
 		}
-		return toSnakeCase(structName) + "s"
+		if name, ok := tableNames[st.StructName]; ok {
+			return name
+		}
+		if slices.Contains(st.Tags, "gorm") {
+			return cfg.Naming.TableName(st.StructName)
+		}
+		return toSnakeCase(st.StructName) + "s"
 	}
 	tmpl, err := template.New("metamodel").Funcs(template.FuncMap{
-		"tableName": tableNameFn,
+		"tableName":   tableNameFn,
+		"nestedTypes": nestedTypes,
+		"fieldType":   fieldType,
+		"fieldValue":  fieldValue,
+		"tagNames":    tagNames,
+		"hasField":    hasField,
+		"ownable":     ownable,
 	}).Parse(metamodelTemplate)
 	if err != nil {
 		return fmt.Errorf("failed to parse template: %w", err)
@@ -112,62 +307,275 @@ func Generate(cfg Config) error {
 		formatted = buf.Bytes()
 	}
 	// Write to destination file
-	if err := os.WriteFile(destPath, formatted, 0644); err != nil {
+	if err := os.WriteFile(unit.DestPath, formatted, 0644); err != nil {
 		return fmt.Errorf("failed to write output file: %w", err)
 	}
-	destDir := filepath.Dir(destPath)
-	if err := generateCommonFile(pkgName, destDir); err != nil {
-		return fmt.Errorf("failed to write common file: %w", err)
+	return nil
+}
+
+// typeImport is a package imported by a metamodel file for the type
+// arguments of its fields.
+type typeImport struct {
+	Name string // explicit package name, empty when it is the last path element
+	Path string
+}
+
+// indexImports are the packages used by the Indexes method of structs
+// declaring MongoDB indexes.
+var indexImports = map[string]string{
+	"bson":    "go.mongodb.org/mongo-driver/v2/bson",
+	"mongo":   "go.mongodb.org/mongo-driver/v2/mongo",
+	"options": "go.mongodb.org/mongo-driver/v2/mongo/options",
+}
+
+// typeImports collects the imports used by the field type arguments of
+// structs, sorted by package name. Two import paths used under the same name
+// cannot share one generated file.
+func typeImports(structs []StructMeta) ([]typeImport, error) {
+	paths := make(map[string]string)
+	var walk func(fields []FieldMeta) error
+	walk = func(fields []FieldMeta) error {
+		for _, f := range fields {
+			for name, path := range f.TypeImports {
+				if prev, ok := paths[name]; ok && prev != path {
+					return fmt.Errorf("field %s: package name %s refers to both %s and %s, rename one of the imports", f.FieldName, name, prev, path)
+				}
+				paths[name] = path
+			}
+			if err := walk(f.Fields); err != nil {
+				return err
+			}
+		}
+		return nil
 	}
-	// When tag is gorm, also generate the shared Field type file
-	if err := generateOperatorFile(pkgName, destDir); err != nil {
-		return fmt.Errorf("failed to write gorm field file: %w", err)
+	for _, st := range structs {
+		if err := walk(st.Fields); err != nil {
+			return nil, fmt.Errorf("struct %s: %w", st.StructName, err)
+		}
 	}
-	if err := generateMongoOperatorFile(pkgName, destDir); err != nil {
-		return fmt.Errorf("failed to write mongo operator file: %w", err)
+	for _, st := range structs {
+		if len(st.Indexes) == 0 {
+			continue
+		}
+		// The Indexes method builds mongo.IndexModel values.
+		for name, path := range indexImports {
+			if prev, ok := paths[name]; ok && prev != path {
+				return nil, fmt.Errorf("struct %s: package name %s of the index models refers to %s, rename the import", st.StructName, name, prev)
+			}
+			paths[name] = path
+		}
 	}
-	return nil
+	var imports []typeImport
+	for _, name := range slices.Sorted(maps.Keys(paths)) {
+		imp := typeImport{Name: name, Path: paths[name]}
+		if path.Base(imp.Path) == name {
+			imp.Name = ""
+		}
+		imports = append(imports, imp)
+	}
+	return imports, nil
 }
 
-func generateCommonFile(pkgName, destDir string) error {
-	tmpl, err := template.New("common").Parse(commonTemplate)
-	if err != nil {
-		return err
+// nestedType is a generated struct type holding the fields of a nested document.
+type nestedType struct {
+	TypeName string
+	Path     string // Go selector of the document, e.g. User.Address
+	Slice    bool   // an array of documents, embedding SliceField for the array operators
+	Array    bool   // an array of documents, given At and the positional helpers
+	Owner    bool   // given WithOwner, unless a field would clash with it
+	Fields   []nestedTypeField
+}
+
+type nestedTypeField struct {
+	FieldName string
+	Type      string
+}
+
+// nestedTypes lists the types needed for the nested documents of st, named
+// after their path: User.Address.Geo is typed User_Address_Geo.
+func nestedTypes(st StructMeta) []nestedType {
+	var types []nestedType
+	// walk appends the types of the documents among fields and reports
+	// whether they can all be given an owner.
+	var walk func(typeName, path string, fields []FieldMeta) bool
+	walk = func(typeName, path string, fields []FieldMeta) bool {
+		owner := true
+		for _, f := range fields {
+			if len(f.Fields) == 0 {
+				continue
+			}
+			nt := nestedType{TypeName: fieldType(typeName, f), Path: path + "." + f.FieldName, Slice: f.Kind == KindSlice, Array: f.Kind == KindSlice, Owner: true}
+			for _, child := range f.Fields {
+				nt.Fields = append(nt.Fields, nestedTypeField{FieldName: child.FieldName, Type: fieldType(nt.TypeName, child)})
+				// the field would clash with the method
+				if slices.Contains([]string{"At", "Positional", "AllPositional"}, child.FieldName) {
+					nt.Array = false
+				}
+				if child.FieldName == "WithOwner" {
+					nt.Owner = false
+				}
+			}
+			i := len(types)
+			types = append(types, nt)
+			types[i].Owner = walk(nt.TypeName, nt.Path, f.Fields) && nt.Owner
+			owner = owner && types[i].Owner
+		}
+		return owner
 	}
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, struct{ PackageName string }{pkgName}); err != nil {
-		return err
+	walk(st.Name, st.StructName, st.Fields)
+	return types
+}
+
+// fieldType returns the Go type of a generated field declared in typeName.
+func fieldType(typeName string, f FieldMeta) string {
+	if len(f.Fields) > 0 {
+		return typeName + "_" + f.FieldName
 	}
-	formatted, err := format.Source(buf.Bytes())
-	if err != nil {
-		formatted = buf.Bytes()
+	return fieldKindName(f) + "[" + fieldTypeArg(f) + "]"
+}
+
+func fieldKindName(f FieldMeta) string {
+	if f.Kind == "" {
+		return KindField
 	}
-	return os.WriteFile(filepath.Join(destDir, "common_metamodel.go"), formatted, 0644)
+	return f.Kind
 }
 
-func generateMongoOperatorFile(pkgName, destDir string) error {
-	filePath := filepath.Join(destDir, "mongo_operator_metamodel.go")
-	tmpl, err := template.New("mongo_operator").Delims("[[", "]]").Parse(mongoFieldTemplate)
-	if err != nil {
-		return err
+func fieldTypeArg(f FieldMeta) string {
+	if f.TypeArg == "" {
+		return "any"
 	}
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, struct{ PackageName string }{pkgName}); err != nil {
-		return err
+	return f.TypeArg
+}
+
+// fieldValue returns the composite literal initializing a generated field.
+func fieldValue(typeName string, f FieldMeta, tableName string) string {
+	typeArg := fieldTypeArg(f)
+	if len(f.Fields) > 0 {
+		typeArg = "any"
 	}
-	formatted, err := format.Source(buf.Bytes())
-	if err != nil {
-		formatted = buf.Bytes()
+	field := fmt.Sprintf("Field[%s]{FieldName: %q, TableName: %q", typeArg, f.TagName, tableName)
+	for _, tag := range slices.Sorted(maps.Keys(f.Names)) {
+		field += fmt.Sprintf(", %s: %q", multiTagFields[tag], f.Names[tag])
+	}
+	field += "}"
+	if len(f.Fields) == 0 {
+		if kind := fieldKindName(f); kind != KindField {
+			return fmt.Sprintf("%s[%s]{Field: %s}", kind, typeArg, field)
+		}
+		return field
+	}
+	nestedName := fieldType(typeName, f)
+	var b strings.Builder
+	if f.Kind == KindSlice {
+		fmt.Fprintf(&b, "%s{\nSliceField: SliceField[any]{Field: %s},\n", nestedName, field)
+	} else {
+		fmt.Fprintf(&b, "%s{\nField: %s,\n", nestedName, field)
 	}
-	return os.WriteFile(filePath, formatted, 0644)
+	for _, child := range f.Fields {
+		fmt.Fprintf(&b, "%s: %s,\n", child.FieldName, fieldValue(nestedName, child, tableName))
+	}
+	b.WriteString("}")
+	return b.String()
 }
 
-func generateOperatorFile(pkgName, destDir string) error {
-	fieldFilePath := filepath.Join(destDir, "gorm_operator_metamodel.go")
-	tmpl, err := template.New("operator").Parse(gormFieldTemplate)
-	if err != nil {
-		return err
+// hasField reports whether st has a top-level field called name, whose
+// generated field would clash with a method of the same name.
+func hasField(st StructMeta, name string) bool {
+	return slices.ContainsFunc(st.Fields, func(f FieldMeta) bool { return f.FieldName == name })
+}
+
+// ownable reports whether every document of st can be given an owner, which
+// As needs to qualify their fields.
+func ownable(st StructMeta) bool {
+	return !slices.ContainsFunc(nestedTypes(st), func(nt nestedType) bool { return !nt.Owner })
+}
+
+// tagNames returns the TagNames literal translating between the tags of st,
+// or "" when st was generated from a single tag.
+func tagNames(st StructMeta) string {
+	if len(st.Tags) < 2 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("TagNames{\n")
+	var walk func(fields []FieldMeta)
+	walk = func(fields []FieldMeta) {
+		for _, f := range fields {
+			b.WriteString("{")
+			for i, tag := range slices.Sorted(maps.Keys(f.Names)) {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				fmt.Fprintf(&b, "%q: %q", tag, f.Names[tag])
+			}
+			b.WriteString("},\n")
+			walk(f.Fields)
+		}
+	}
+	walk(st.Fields)
+	b.WriteString("}")
+	return b.String()
+}
+
+// parseTableNames interprets the -tableName flag: "Struct=table" pairs naming
+// structs of the source, or a bare table name when the source holds a single
+// struct. Anything else is an error rather than a table given to the wrong
+// struct, since a pattern such as ./... parses many of them.
+func parseTableNames(flag string, units []sourceUnit) (map[string]string, error) {
+	tableNames := make(map[string]string)
+	if flag == "" {
+		return tableNames, nil
+	}
+	var structs []string
+	for _, unit := range units {
+		for _, st := range unit.Structs {
+			structs = append(structs, st.StructName)
+		}
 	}
+	if !strings.Contains(flag, "=") {
+		if len(structs) != 1 {
+			return nil, fmt.Errorf("table name %q needs a struct: the source has %d structs, name it with Struct=table", flag, len(structs))
+		}
+		tableNames[structs[0]] = flag
+		return tableNames, nil
+	}
+	for _, pair := range strings.Split(flag, ",") {
+		structName, table, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || structName == "" || table == "" {
+			return nil, fmt.Errorf("invalid table name %q, want Struct=table", pair)
+		}
+		if !slices.Contains(structs, structName) {
+			return nil, fmt.Errorf("table name %q: no struct %s in the source", pair, structName)
+		}
+		tableNames[structName] = table
+	}
+	return tableNames, nil
+}
+
+// sharedFiles are written once into every destination directory. The Field
+// types, the MongoDB operators and the query builder are written for every
+// metamodel; the other MongoDB helpers only for structs read from bson tags
+// and the other SQL builders only for those read from gorm tags. The Mongo
+// templates are delimited by [[ ]], as their code is full of bson.D{{...}}.
+var sharedFiles = []struct {
+	name string
+	tag  string // struct tag the file is written for, "" for every metamodel
+	tmpl *template.Template
+}{
+	{"common_metamodel.go", "", template.Must(template.New("common").Parse(commonTemplate))},
+	{"gorm_operator_metamodel.go", "", template.Must(template.New("operator").Parse(gormFieldTemplate))},
+	{"mongo_operator_metamodel.go", "", template.Must(template.New("mongo_operator").Delims("[[", "]]").Parse(mongoFieldTemplate))},
+	{"mongo_pipeline_metamodel.go", "bson", template.Must(template.New("mongo_pipeline").Delims("[[", "]]").Parse(mongoPipelineTemplate))},
+	{"mongo_query_metamodel.go", "bson", template.Must(template.New("mongo_query").Delims("[[", "]]").Parse(mongoQueryTemplate))},
+	{"mongo_index_metamodel.go", "bson", template.Must(template.New("mongo_index").Delims("[[", "]]").Parse(mongoIndexTemplate))},
+	{"sql_builder_metamodel.go", "", template.Must(template.New("sql_builder").Parse(sqlBuilderTemplate))},
+	{"sql_statement_metamodel.go", "gorm", template.Must(template.New("sql_statement").Parse(sqlStatementTemplate))},
+	{"sql_function_metamodel.go", "gorm", template.Must(template.New("sql_function").Parse(sqlFunctionTemplate))},
+}
+
+// writeSharedFile executes tmpl for the package pkgName into destDir/name.
+func writeSharedFile(tmpl *template.Template, name, pkgName, destDir string) error {
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, struct{ PackageName string }{pkgName}); err != nil {
 		return err
@@ -176,5 +584,5 @@ func generateOperatorFile(pkgName, destDir string) error {
 	if err != nil {
 		formatted = buf.Bytes()
 	}
-	return os.WriteFile(fieldFilePath, formatted, 0644)
+	return os.WriteFile(filepath.Join(destDir, name), formatted, 0644)
 }
@@ -121,6 +121,161 @@ func TestGenerate_CustomTableName(t *testing.T) {
 	assertContains(t, content, `TableName: "my_users"`)
 }
 
+const multiStructFixture = `package models
+
+type User struct {
+	ID int ` + "`json:\"id\"`" + `
+}
+
+type Order struct {
+	ID int ` + "`json:\"id\"`" + `
+}
+
+type Invoice struct {
+	ID int ` + "`json:\"id\"`" + `
+}
+
+func (Invoice) TableName() string { return "billing_invoices" }
+`
+
+func TestGenerate_TableNameFlagBareName(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "models.go")
+	mustWriteFile(t, src, `package models
+
+type User struct {
+	ID int `+"`json:\"id\"`"+`
+}
+`)
+
+	cfg := Config{Source: src, Destination: dir + "/", PackageName: "metamodel", Tag: "json", TableName: "accounts"}
+	if err := Generate(cfg); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	assertContains(t, mustReadFile(t, filepath.Join(dir, "models_metamodel.go")), `TableName: "accounts"`)
+
+	// a bare name is ambiguous once the source has several structs
+	mustWriteFile(t, src, multiStructFixture)
+	if err := Generate(cfg); err == nil || !strings.Contains(err.Error(), "Struct=table") {
+		t.Errorf("Generate() error = %v, want an error asking for Struct=table", err)
+	}
+}
+
+func TestGenerate_TableNameFlagPairs(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "models.go")
+	mustWriteFile(t, src, multiStructFixture)
+
+	cfg := Config{Source: src, Destination: dir + "/", PackageName: "metamodel", Tag: "json", TableName: "Order=purchase_orders, Invoice=ignored"}
+	if err := Generate(cfg); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	content := mustReadFile(t, filepath.Join(dir, "models_metamodel.go"))
+	assertContains(t, content, `TableName: "users"`)
+	assertContains(t, content, `TableName: "purchase_orders"`)
+	assertContains(t, content, `TableName: "billing_invoices"`)
+	assertNotContains(t, content, `"ignored"`)
+
+	cfg.TableName = "Order="
+	if err := Generate(cfg); err == nil {
+		t.Fatal("expected error for malformed table name pair, got nil")
+	}
+	cfg.TableName = "Order=purchase_orders,Ordr=typo"
+	if err := Generate(cfg); err == nil || !strings.Contains(err.Error(), "no struct Ordr") {
+		t.Errorf("Generate() error = %v, want an error for the unknown struct Ordr", err)
+	}
+}
+
+func TestGenerate_NameDirective(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "models.go")
+	mustWriteFile(t, src, `package models
+
+//metamodel:name=Account
+type User struct {
+	ID int `+"`json:\"id\"`"+`
+}
+`)
+
+	cfg := Config{Source: src, Destination: dir + "/", PackageName: "metamodel", Tag: "json"}
+	if err := Generate(cfg); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	content := mustReadFile(t, filepath.Join(dir, "models_metamodel.go"))
+	assertContains(t, content, `var Account_ =`)
+	assertNotContains(t, content, `var User_ =`)
+	// The default table name still follows the struct
+	assertContains(t, content, `TableName: "users"`)
+}
+
+func TestGenerate_GORMNamingStrategy(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "models.go")
+	mustWriteFile(t, src, `package models
+
+type Category struct {
+	Name string
+}
+
+type OrderItem struct {
+	Quantity int
+}
+
+func (OrderItem) TableName() string { return "line_items" }
+`)
+
+	cfg := Config{Source: src, Destination: dir + "/", PackageName: "metamodel", Tag: "gorm"}
+	if err := Generate(cfg); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	content := mustReadFile(t, filepath.Join(dir, "models_metamodel.go"))
+	assertContains(t, content, `TableName: "categories"`)
+	assertContains(t, content, `FieldName: "quantity"`)
+
+	cfg.Naming = NamingStrategy{TablePrefix: "shop_", SingularTable: true}
+	if err := Generate(cfg); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	content = mustReadFile(t, filepath.Join(dir, "models_metamodel.go"))
+	assertContains(t, content, `TableName: "shop_category"`)
+	// TableName() is used verbatim, like GORM does
+	assertContains(t, content, `TableName: "line_items"`)
+}
+
+func TestGenerate_NestedDocuments(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "models.go")
+	mustWriteFile(t, src, `package models
+
+type Geo struct {
+	Lat float64 `+"`bson:\"lat\"`"+`
+}
+
+type Address struct {
+	City string `+"`bson:\"city\"`"+`
+	Geo  Geo    `+"`bson:\"geo\"`"+`
+}
+
+type User struct {
+	Address Address `+"`bson:\"address\"`"+`
+}
+`)
+
+	cfg := Config{Source: src, Destination: dir + "/", PackageName: "metamodel", Tag: "bson"}
+	if err := Generate(cfg); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	content := mustReadFile(t, filepath.Join(dir, "models_metamodel.go"))
+	assertContains(t, content, "type User_Address struct {")
+	assertContains(t, content, "type User_Address_Geo struct {")
+	assertContains(t, content, "User_Address{")
+	assertContains(t, content, `FieldName: "address.city"`)
+	assertContains(t, content, `FieldName: "address.geo.lat"`)
+}
+
 func TestGenerate_DefaultDestination(t *testing.T) {
 	dir := t.TempDir()
 	src := filepath.Join(dir, "models.go")
@@ -146,42 +301,162 @@ func TestGenerate_CreatesCommonAndOperatorFiles(t *testing.T) {
 		t.Fatalf("Generate() error = %v", err)
 	}
 
-	for _, name := range []string{"common_metamodel.go", "gorm_operator_metamodel.go", "mongo_operator_metamodel.go"} {
+	for _, name := range []string{"common_metamodel.go", "gorm_operator_metamodel.go", "mongo_operator_metamodel.go", "sql_builder_metamodel.go"} {
 		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
 			t.Errorf("expected %s to be created: %v", name, err)
 		}
 	}
 }
 
-func TestGenerate_NoStructsReturnsError(t *testing.T) {
-	dir := t.TempDir()
-	src := filepath.Join(dir, "empty.go")
-	mustWriteFile(t, src, "package models\n")
+func TestGenerate_SharedFilesOfTags(t *testing.T) {
+	mongoFiles := []string{"mongo_pipeline_metamodel.go", "mongo_query_metamodel.go", "mongo_index_metamodel.go"}
+	sqlFiles := []string{"sql_statement_metamodel.go", "sql_function_metamodel.go"}
+	tests := []struct {
+		tag        string
+		mongo, sql bool
+	}{
+		{tag: "json"},
+		{tag: "bson", mongo: true},
+		{tag: "gorm", sql: true},
+		{tag: "json,bson,gorm", mongo: true, sql: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			dir := t.TempDir()
+			src := filepath.Join(dir, "models.go")
+			mustWriteFile(t, src, `package models
 
-	err := Generate(Config{Source: src, Tag: "json"})
-	if err == nil {
-		t.Fatal("expected error for file with no structs, got nil")
+type User struct {
+	ID int `+"`json:\"id\" bson:\"_id\" gorm:\"column:id\"`"+`
+}
+`)
+
+			cfg := Config{Source: src, Destination: dir + "/", PackageName: "metamodel", Tag: tt.tag}
+			if err := Generate(cfg); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			want := make(map[string]bool)
+			for _, name := range mongoFiles {
+				want[name] = tt.mongo
+			}
+			for _, name := range sqlFiles {
+				want[name] = tt.sql
+			}
+			for name, created := range want {
+				_, err := os.Stat(filepath.Join(dir, name))
+				if created && err != nil {
+					t.Errorf("expected %s to be created: %v", name, err)
+				}
+				if !created && err == nil {
+					t.Errorf("unexpected %s for -tag=%s", name, tt.tag)
+				}
+			}
+		})
 	}
 }
 
-func TestGenerate_ExplicitDestFile(t *testing.T) {
+func TestGenerate_PackagePattern(t *testing.T) {
 	dir := t.TempDir()
-	src := filepath.Join(dir, "models.go")
-	dest := filepath.Join(dir, "out", "gen.go")
-	mustWriteFile(t, src, jsonFixture)
+	mustMkdir(t, filepath.Join(dir, "src", "users"))
+	mustMkdir(t, filepath.Join(dir, "src", "shop", "orders"))
+	mustMkdir(t, filepath.Join(dir, "src", "testdata"))
+	mustWriteFile(t, filepath.Join(dir, "src", "users", "user.go"), jsonFixture)
+	mustWriteFile(t, filepath.Join(dir, "src", "shop", "orders", "order.go"), `package orders
+
+type Order struct {
+	ID int `+"`json:\"order_id\"`"+`
+}
+`)
+	mustWriteFile(t, filepath.Join(dir, "src", "testdata", "ignored.go"), `package testdata
 
-	cfg := Config{Source: src, Destination: dest, PackageName: "metamodel", Tag: "json"}
+type Ignored struct {
+	ID int `+"`json:\"id\"`"+`
+}
+`)
+	out := filepath.Join(dir, "out") + "/"
+
+	cfg := Config{Source: filepath.Join(dir, "src") + "/...", Destination: out, PackageName: "metamodel", Tag: "json"}
 	if err := Generate(cfg); err != nil {
 		t.Fatalf("Generate() error = %v", err)
 	}
 
-	if _, err := os.Stat(dest); err != nil {
-		t.Errorf("expected %s to exist: %v", dest, err)
+	assertContains(t, mustReadFile(t, filepath.Join(out, "users_metamodel.go")), `var User_ =`)
+	assertContains(t, mustReadFile(t, filepath.Join(out, "orders_metamodel.go")), `var Order_ =`)
+	if _, err := os.Stat(filepath.Join(out, "testdata_metamodel.go")); err == nil {
+		t.Error("testdata directories must be skipped")
+	}
+	for _, name := range []string{"common_metamodel.go", "gorm_operator_metamodel.go", "mongo_operator_metamodel.go"} {
+		if _, err := os.Stat(filepath.Join(out, name)); err != nil {
+			t.Errorf("expected %s to be created: %v", name, err)
+		}
+	}
+}
+
+func TestGenerate_PackagePatternDuplicateStruct(t *testing.T) {
+	dir := t.TempDir()
+	mustMkdir(t, filepath.Join(dir, "a"))
+	mustMkdir(t, filepath.Join(dir, "b"))
+	mustWriteFile(t, filepath.Join(dir, "a", "user.go"), jsonFixture)
+	mustWriteFile(t, filepath.Join(dir, "b", "user.go"), jsonFixture)
+
+	cfg := Config{Source: dir + "/...", Destination: filepath.Join(dir, "out") + "/", PackageName: "metamodel", Tag: "json"}
+	if err := Generate(cfg); err == nil {
+		t.Fatal("expected error for struct declared in two packages, got nil")
+	}
+}
+
+func TestGenerate_PackagePatternSamePackageName(t *testing.T) {
+	dir := t.TempDir()
+	mustMkdir(t, filepath.Join(dir, "a", "models"))
+	mustMkdir(t, filepath.Join(dir, "b", "models"))
+	mustWriteFile(t, filepath.Join(dir, "a", "models", "user.go"), jsonFixture)
+	mustWriteFile(t, filepath.Join(dir, "b", "models", "order.go"), `package models
+
+type Order struct {
+	ID int `+"`json:\"order_id\"`"+`
+}
+`)
+
+	cfg := Config{Source: dir + "/...", Destination: filepath.Join(dir, "out") + "/", PackageName: "metamodel", Tag: "json"}
+	err := Generate(cfg)
+	if err == nil {
+		t.Fatal("expected error for two packages writing models_metamodel.go, got nil")
+	}
+	for _, want := range []string{filepath.Join(dir, "a", "models"), filepath.Join(dir, "b", "models"), "models_metamodel.go"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+}
+
+func TestGenerate_PackagePatternSingleDestFile(t *testing.T) {
+	dir := t.TempDir()
+	mustMkdir(t, filepath.Join(dir, "a"))
+	mustMkdir(t, filepath.Join(dir, "b"))
+	mustWriteFile(t, filepath.Join(dir, "a", "user.go"), jsonFixture)
+	mustWriteFile(t, filepath.Join(dir, "b", "order.go"), `package models
+
+type Order struct {
+	ID int `+"`json:\"order_id\"`"+`
+}
+`)
+
+	cfg := Config{Source: dir + "/...", Destination: filepath.Join(dir, "gen.go"), PackageName: "metamodel", Tag: "json"}
+	if err := Generate(cfg); err == nil {
+		t.Fatal("expected error when several packages target one file, got nil")
 	}
 }
 
 // ---- helpers --------------------------------------------------------------------
 
+func mustMkdir(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(path, 0755); err != nil {
+		t.Fatalf("failed to create %s: %v", path, err)
+	}
+}
+
 func mustWriteFile(t *testing.T, path, content string) {
 	t.Helper()
 	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
@@ -211,3 +486,280 @@ func assertNotContains(t *testing.T, content, substr string) {
 		t.Errorf("expected output NOT to contain %q", substr)
 	}
 }
+
+func TestGenerate_MultipleTags(t *testing.T) {
+	dir := t.TempDir()
+	src := dir + "/models.go"
+	mustWriteFile(t, src, `package models
+
+type User struct {
+	Email string `+"`json:\"emailAddress\" bson:\"email\" gorm:\"column:email_address\"`"+`
+}
+`)
+	cfg := Config{Source: src, Destination: dir + "/", PackageName: "metamodel", Tag: "json,bson,gorm"}
+	if err := Generate(cfg); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	content, err := os.ReadFile(dir + "/models_metamodel.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`Field[string]{FieldName: "emailAddress", TableName: "users", BSON: "email", Column: "email_address", JSON: "emailAddress"}`,
+		`TagNames: TagNames{`,
+		`{"bson": "email", "gorm": "email_address", "json": "emailAddress"},`,
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("generated code missing %q:\n%s", want, content)
+		}
+	}
+}
+
+func TestGenerate_FieldKinds(t *testing.T) {
+	dir := t.TempDir()
+	src := dir + "/models.go"
+	mustWriteFile(t, src, `package models
+
+type Item struct {
+	Name   string  `+"`gorm:\"column:name\"`"+`
+	Active bool    `+"`gorm:\"column:active\"`"+`
+	Price  float64 `+"`gorm:\"column:price\"`"+`
+	Data   []byte  `+"`gorm:\"column:data\"`"+`
+}
+`)
+	cfg := Config{Source: src, Destination: dir + "/", PackageName: "metamodel", Tag: "gorm"}
+	if err := Generate(cfg); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	content, err := os.ReadFile(dir + "/models_metamodel.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`StringField[string]{Field: Field[string]{FieldName: "name", TableName: "items"}},`,
+		`BoolField[bool]{Field: Field[bool]{FieldName: "active", TableName: "items"}},`,
+		`NumberField[float64]{Field: Field[float64]{FieldName: "price", TableName: "items"}},`,
+		`Field[[]byte]{FieldName: "data", TableName: "items"},`,
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("generated code missing %q:\n%s", want, content)
+		}
+	}
+}
+
+func TestGenerate_TypeArgumentImports(t *testing.T) {
+	dir := t.TempDir()
+	src := dir + "/models.go"
+	mustWriteFile(t, src, `package models
+
+import (
+	"time"
+
+	id "github.com/gofrs/uuid"
+)
+
+type Status int
+
+type Event struct {
+	ID        id.UUID    `+"`gorm:\"column:id\"`"+`
+	Status    Status     `+"`gorm:\"column:status\"`"+`
+	CreatedAt *time.Time `+"`gorm:\"column:created_at\"`"+`
+}
+`)
+	cfg := Config{Source: src, Destination: dir + "/", PackageName: "metamodel", Tag: "gorm"}
+	if err := Generate(cfg); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	content, err := os.ReadFile(dir + "/models_metamodel.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`id "github.com/gofrs/uuid"`,
+		`"time"`,
+		`Field[id.UUID]{FieldName: "id"`,
+		`NumberField[any]{Field: Field[any]{FieldName: "status"`,
+		`TimeField[time.Time]{Field: Field[time.Time]{FieldName: "created_at"`,
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("generated code missing %q:\n%s", want, content)
+		}
+	}
+}
+
+func TestGenerate_AliasMethod(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "models.go")
+	mustWriteFile(t, src, `package models
+
+type Geo struct {
+	Lat float64 `+"`bson:\"lat\"`"+`
+}
+
+type User struct {
+	Name string `+"`bson:\"name\"`"+`
+	Geo  Geo    `+"`bson:\"geo\"`"+`
+}
+
+type Clash struct {
+	As string `+"`bson:\"as\"`"+`
+}
+
+type Owner struct {
+	WithOwner string `+"`bson:\"with_owner\"`"+`
+}
+
+type Deep struct {
+	Geo   Geo   `+"`bson:\"geo\"`"+`
+	Owner Owner `+"`bson:\"owner\"`"+`
+}
+`)
+	cfg := Config{Source: src, Destination: dir + "/", PackageName: "metamodel", Tag: "bson"}
+	if err := Generate(cfg); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	content := mustReadFile(t, filepath.Join(dir, "models_metamodel.go"))
+	assertContains(t, content, "type UserMetamodel struct {")
+	assertContains(t, content, "var User_ = UserMetamodel{")
+	assertContains(t, content, "func (m UserMetamodel) As(alias string) UserMetamodel {")
+	assertContains(t, content, "m.Name = m.Name.WithOwner(alias)")
+	assertContains(t, content, "m.Geo = m.Geo.WithOwner(alias)")
+	assertContains(t, content, "func (d User_Geo) WithOwner(val string) User_Geo {")
+	assertContains(t, content, "d.Lat = d.Lat.WithOwner(val)")
+	if strings.Contains(content, "func (m ClashMetamodel) As(") {
+		t.Errorf("As method generated for a struct with an As field:\n%s", content)
+	}
+	for _, method := range []string{"func (m DeepMetamodel) As(", "func (d Deep_Owner) WithOwner("} {
+		if strings.Contains(content, method) {
+			t.Errorf("unexpected %s for a document with a WithOwner field:\n%s", method, content)
+		}
+	}
+}
+
+func TestGenerate_PositionalHelpers(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "models.go")
+	mustWriteFile(t, src, `package models
+
+type Geo struct {
+	Lat float64 `+"`bson:\"lat\"`"+`
+}
+
+type Step struct {
+	At string `+"`bson:\"at\"`"+`
+}
+
+type User struct {
+	Geo   Geo    `+"`bson:\"geo\"`"+`
+	Geos  []Geo  `+"`bson:\"geos\"`"+`
+	Steps []Step `+"`bson:\"steps\"`"+`
+}
+`)
+	cfg := Config{Source: src, Destination: dir + "/", PackageName: "metamodel", Tag: "bson"}
+	if err := Generate(cfg); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	content := mustReadFile(t, filepath.Join(dir, "models_metamodel.go"))
+	assertContains(t, content, "type User_Geos struct {\n\tSliceField[any]")
+	assertContains(t, content, "type User_Geo struct {\n\tField[any]")
+	assertContains(t, content, "func (d User_Geos) At(i int) User_Geos {")
+	assertContains(t, content, "func (d User_Geos) Positional() User_Geos {")
+	assertContains(t, content, "func (d User_Geos) AllPositional() User_Geos {")
+	assertContains(t, content, "func (d *User_Geo) rebase(from, to Field[any]) {")
+	for _, method := range []string{"func (d User_Geo) At(", "func (d User_Steps) At("} {
+		if strings.Contains(content, method) {
+			t.Errorf("unexpected %s in:\n%s", method, content)
+		}
+	}
+}
+
+func TestGenerate_Indexes(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "models.go")
+	mustWriteFile(t, src, `package models
+
+import "time"
+
+//metamodel:index=Name,-Score unique name=by_name
+//metamodel:index=Owner.Name sparse partial={"score": {"$gt": 0}}
+type Scenario struct {
+	Name      string    `+"`bson:\"name\"`"+`
+	Score     int       `+"`bson:\"score\"`"+`
+	Email     string    `+"`bson:\"email\" metamodel:\"index,unique,desc\"`"+`
+	ExpiresAt time.Time `+"`bson:\"expires_at\" metamodel:\"index,ttl=24h\"`"+`
+	Owner     Owner     `+"`bson:\"owner\"`"+`
+}
+
+type Owner struct {
+	Name string `+"`bson:\"name\"`"+`
+}
+`)
+	cfg := Config{Source: src, Destination: dir + "/", PackageName: "metamodel", Tag: "bson"}
+	if err := Generate(cfg); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	content := mustReadFile(t, filepath.Join(dir, "models_metamodel.go"))
+	assertContains(t, content, `"go.mongodb.org/mongo-driver/v2/mongo"`)
+	assertContains(t, content, "registerMgoIndexes(Scenario_.TableName, Scenario_.Indexes)")
+	assertContains(t, content, "func (m ScenarioMetamodel) Indexes() []mongo.IndexModel {")
+	assertContains(t, content, "{Key: m.Score.mgoKey(), Value: -1},")
+	assertContains(t, content, `Options: options.Index().SetUnique(true).SetName("by_name"),`)
+	assertContains(t, content, "{Key: m.Owner.Name.mgoKey(), Value: 1},")
+	assertContains(t, content, "Options: options.Index().SetSparse(true).SetPartialFilterExpression(bson.D{{Key: \"score\", Value: bson.D{{Key: \"$gt\", Value: 0}}}}),")
+	assertContains(t, content, "Options: options.Index().SetUnique(true),")
+	assertContains(t, content, "Options: options.Index().SetExpireAfterSeconds(86400),")
+	if strings.Contains(content, "func (m OwnerMetamodel) Indexes()") {
+		t.Errorf("Indexes generated for a struct without indexes:\n%s", content)
+	}
+	assertContains(t, mustReadFile(t, filepath.Join(dir, "mongo_index_metamodel.go")), "func EnsureIndexes(ctx context.Context, db *mongo.Database) error {")
+}
+
+func TestGenerate_IndexesWithoutAnnotations(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "models.go")
+	mustWriteFile(t, src, jsonFixture)
+	cfg := Config{Source: src, Destination: dir + "/", PackageName: "metamodel", Tag: "json"}
+	if err := Generate(cfg); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	content := mustReadFile(t, filepath.Join(dir, "models_metamodel.go"))
+	for _, unwanted := range []string{"mongo-driver", "Indexes()", "registerMgoIndexes"} {
+		if strings.Contains(content, unwanted) {
+			t.Errorf("unexpected %s in:\n%s", unwanted, content)
+		}
+	}
+}
+
+func TestGenerate_InvalidIndexes(t *testing.T) {
+	for name, model := range map[string]string{
+		"unknown field": `//metamodel:index=Missing
+type Scenario struct {
+	Name string ` + "`bson:\"name\"`" + `
+}`,
+		"ttl on several fields": `//metamodel:index=Name,Score ttl=1h
+type Scenario struct {
+	Name  string ` + "`bson:\"name\"`" + `
+	Score int    ` + "`bson:\"score\"`" + `
+}`,
+		"Indexes field": `type Scenario struct {
+	Indexes []string ` + "`bson:\"indexes\" metamodel:\"index\"`" + `
+}`,
+		"unknown tag option": `type Scenario struct {
+	Name string ` + "`bson:\"name\" metamodel:\"index,primary\"`" + `
+}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			src := filepath.Join(dir, "models.go")
+			mustWriteFile(t, src, "package models\n\n"+model+"\n")
+			cfg := Config{Source: src, Destination: dir + "/", PackageName: "metamodel", Tag: "bson"}
+			if err := Generate(cfg); err == nil {
+				t.Error("Generate() expected error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"io"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// indexTagKey is the struct tag declaring a single-field index:
+//
+//	Email     string    `bson:"email" metamodel:"index,unique"`
+//	ExpiresAt time.Time `bson:"expires_at" metamodel:"index,ttl=0s"`
+const indexTagKey = "metamodel"
+
+// IndexMeta is a MongoDB index declared on a struct, by a //metamodel:index
+// directive or a metamodel:"index" struct tag.
+type IndexMeta struct {
+	Keys    []IndexKey
+	Unique  bool
+	Sparse  bool
+	TTL     *int32 // expireAfterSeconds, if set
+	Partial string // partialFilterExpression as a bson.D literal, if set
+	Name    string // index name, MongoDB's default when empty
+}
+
+// IndexKey is a field of an index, named by its metamodel selector such as
+// Owner.Name.
+type IndexKey struct {
+	Field string
+	Desc  bool
+}
+
+// parseIndexDirective reads the value of a //metamodel:index directive: the
+// comma-separated fields of the index, descending ones prefixed with "-",
+// then space-separated options:
+//
+//	//metamodel:index=Status, -CreatedAt unique sparse name=by_status partial={"status": "open"}
+func parseIndexDirective(value string) (IndexMeta, error) {
+	fields, opts := cutIndexFields(value)
+	var idx IndexMeta
+	for _, field := range fields {
+		field, desc := strings.CutPrefix(field, "-")
+		if field == "" {
+			return idx, fmt.Errorf("index %q has an empty field", value)
+		}
+		idx.Keys = append(idx.Keys, IndexKey{Field: field, Desc: desc})
+	}
+	if err := parseIndexOptions(&idx, opts, " ", false); err != nil {
+		return idx, fmt.Errorf("index %q: %w", value, err)
+	}
+	return idx, nil
+}
+
+// cutIndexFields splits the value of a directive into its fields and its
+// options. The fields end at the first space that is not next to a comma, so
+// that they may be listed as "Status, -CreatedAt".
+func cutIndexFields(value string) (fields []string, opts string) {
+	rest := strings.TrimSpace(value)
+	for {
+		end := strings.IndexAny(rest, ", \t")
+		if end < 0 {
+			return append(fields, rest), ""
+		}
+		fields = append(fields, rest[:end])
+		rest = strings.TrimLeft(rest[end:], " \t")
+		if !strings.HasPrefix(rest, ",") {
+			return fields, rest
+		}
+		rest = strings.TrimLeft(rest[1:], " \t")
+	}
+}
+
+// parseIndexTag reads the metamodel:"index,..." tag of a field: "index"
+// followed by comma-separated options, "desc" ordering the field descending.
+func parseIndexTag(field, tag string) (IndexMeta, error) {
+	kind, opts, _ := strings.Cut(tag, ",")
+	idx := IndexMeta{Keys: []IndexKey{{Field: field}}}
+	if kind != "index" {
+		return idx, fmt.Errorf("field %s: unknown %s tag %q", field, indexTagKey, tag)
+	}
+	if err := parseIndexOptions(&idx, opts, ",", true); err != nil {
+		return idx, fmt.Errorf("field %s: %w", field, err)
+	}
+	return idx, nil
+}
+
+// parseIndexOptions applies the options of an index, separated by sep. The
+// partial filter may hold sep itself, so it must be the last option.
+func parseIndexOptions(idx *IndexMeta, opts, sep string, allowDesc bool) error {
+	for opts = strings.TrimSpace(opts); opts != ""; opts = strings.TrimSpace(opts) {
+		var opt string
+		if strings.HasPrefix(opts, "partial=") {
+			opt, opts = opts, ""
+		} else {
+			opt, opts, _ = strings.Cut(opts, sep)
+		}
+		key, value, hasValue := strings.Cut(strings.TrimSpace(opt), "=")
+		value = strings.TrimSpace(value)
+		switch key {
+		case "unique", "sparse", "desc":
+			if hasValue {
+				return fmt.Errorf("option %s takes no value", key)
+			}
+		case "ttl", "name", "partial":
+			if value == "" {
+				return fmt.Errorf("option %s requires a value", key)
+			}
+		}
+		switch key {
+		case "unique":
+			idx.Unique = true
+		case "sparse":
+			idx.Sparse = true
+		case "desc":
+			if !allowDesc {
+				return fmt.Errorf("option desc is for tags, prefix the field with - instead")
+			}
+			idx.Keys[0].Desc = true
+		case "ttl":
+			d, err := time.ParseDuration(value)
+			if err != nil || d < 0 || d%time.Second != 0 || d/time.Second > math.MaxInt32 {
+				return fmt.Errorf("ttl %s is not a whole number of seconds such as 24h or 0s", value)
+			}
+			seconds := int32(d / time.Second)
+			idx.TTL = &seconds
+		case "name":
+			idx.Name = value
+		case "partial":
+			filter, err := bsonLiteral(value)
+			if err != nil {
+				return fmt.Errorf("partial filter %s: %w", value, err)
+			}
+			idx.Partial = filter
+		default:
+			return fmt.Errorf("unknown option %s", key)
+		}
+	}
+	return nil
+}
+
+// extJSONWrappers are the keys of the extended JSON values standing for BSON
+// types, such as {"$date": ...}, which a bson.D literal cannot spell.
+var extJSONWrappers = []string{
+	"$binary", "$code", "$date", "$dbPointer", "$maxKey", "$minKey", "$numberDecimal",
+	"$numberDouble", "$numberInt", "$numberLong", "$oid", "$regularExpression",
+	"$scope", "$symbol", "$timestamp", "$undefined", "$uuid",
+}
+
+// bsonLiteral returns the bson.D literal of a JSON object, keeping the order
+// of its keys, so that the generated code builds a partial filter without
+// decoding it at run time:
+//
+//	{"score": {"$gt": 0}} → bson.D{{Key: "score", Value: bson.D{{Key: "$gt", Value: 0}}}}
+func bsonLiteral(filter string) (string, error) {
+	dec := json.NewDecoder(strings.NewReader(filter))
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return "", fmt.Errorf("not a JSON object")
+	}
+	var b strings.Builder
+	if err := writeBSONValue(&b, dec, json.Delim('{')); err != nil {
+		return "", err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return "", fmt.Errorf("not a JSON object")
+	}
+	return b.String(), nil
+}
+
+// writeBSONValue writes the Go literal of the JSON value starting with tok:
+// bson.D for objects, bson.A for arrays.
+func writeBSONValue(b *strings.Builder, dec *json.Decoder, tok json.Token) error {
+	switch tok := tok.(type) {
+	case json.Delim:
+		object := tok == '{'
+		if object {
+			b.WriteString("bson.D{")
+		} else {
+			b.WriteString("bson.A{")
+		}
+		for i := 0; dec.More(); i++ {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			if object {
+				key, err := dec.Token()
+				if err != nil {
+					return fmt.Errorf("not a JSON object")
+				}
+				if slices.Contains(extJSONWrappers, key.(string)) {
+					return fmt.Errorf("extended JSON value %s is not supported", key)
+				}
+				fmt.Fprintf(b, "{Key: %q, Value: ", key)
+			}
+			val, err := dec.Token()
+			if err != nil {
+				return fmt.Errorf("not a JSON object")
+			}
+			if err := writeBSONValue(b, dec, val); err != nil {
+				return err
+			}
+			if object {
+				b.WriteString("}")
+			}
+		}
+		b.WriteString("}")
+		if _, err := dec.Token(); err != nil {
+			return fmt.Errorf("not a JSON object")
+		}
+	case json.Number:
+		if _, err := tok.Int64(); err == nil {
+			b.WriteString(tok.String())
+		} else if _, err := tok.Float64(); err == nil {
+			fmt.Fprintf(b, "float64(%s)", tok)
+		} else {
+			return fmt.Errorf("number %s is out of range", tok)
+		}
+	case string:
+		fmt.Fprintf(b, "%q", tok)
+	case bool:
+		fmt.Fprint(b, tok)
+	case nil:
+		b.WriteString("nil")
+	}
+	return nil
+}
+
+// structIndexes returns the indexes declared on a struct: those of its
+// directives, then those tagged on its fields, checking that they name
+// fields of the metamodel.
+func structIndexes(structType *ast.StructType, directives []IndexMeta, fields []FieldMeta) ([]IndexMeta, error) {
+	indexes := append([]IndexMeta(nil), directives...)
+	for _, field := range structType.Fields.List {
+		if field.Tag == nil {
+			continue
+		}
+		tag, ok := reflect.StructTag(strings.Trim(field.Tag.Value, "`")).Lookup(indexTagKey)
+		if !ok {
+			continue
+		}
+		if len(field.Names) != 1 {
+			return nil, fmt.Errorf("%s tag %q must be on a named field", indexTagKey, tag)
+		}
+		idx, err := parseIndexTag(field.Names[0].Name, tag)
+		if err != nil {
+			return nil, err
+		}
+		indexes = append(indexes, idx)
+	}
+	if len(indexes) == 0 {
+		return nil, nil
+	}
+	if slices.ContainsFunc(fields, func(f FieldMeta) bool { return f.FieldName == "Indexes" }) {
+		return nil, fmt.Errorf("indexes cannot be generated for a struct with a field named Indexes")
+	}
+	for _, idx := range indexes {
+		for _, key := range idx.Keys {
+			if !hasFieldPath(fields, key.Field) {
+				return nil, fmt.Errorf("index field %s is not a field of the metamodel", key.Field)
+			}
+		}
+		if idx.TTL != nil && len(idx.Keys) > 1 {
+			return nil, fmt.Errorf("ttl index on %d fields, MongoDB only expires documents by a single field", len(idx.Keys))
+		}
+	}
+	return indexes, nil
+}
+
+// hasFieldPath reports whether the dotted selector path names a generated field.
+func hasFieldPath(fields []FieldMeta, path string) bool {
+	name, rest, nested := strings.Cut(path, ".")
+	for _, f := range fields {
+		if f.FieldName != name {
+			continue
+		}
+		if !nested {
+			return true
+		}
+		return hasFieldPath(f.Fields, rest)
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+)
+
+// ---- index annotations -----------------------------------------------------------
+
+func TestParseIndexDirective(t *testing.T) {
+	ttl := int32(3600)
+	tests := []struct {
+		value string
+		want  IndexMeta
+	}{
+		{"Email", IndexMeta{Keys: []IndexKey{{Field: "Email"}}}},
+		{"Status,-CreatedAt unique sparse name=by_status", IndexMeta{
+			Keys:   []IndexKey{{Field: "Status"}, {Field: "CreatedAt", Desc: true}},
+			Unique: true,
+			Sparse: true,
+			Name:   "by_status",
+		}},
+		{"ExpiresAt ttl=1h", IndexMeta{Keys: []IndexKey{{Field: "ExpiresAt"}}, TTL: &ttl}},
+		{`Owner.Name  unique  partial={"status": "open", "n": {"$gt": 1}}`, IndexMeta{
+			Keys:    []IndexKey{{Field: "Owner.Name"}},
+			Unique:  true,
+			Partial: `bson.D{{Key: "status", Value: "open"}, {Key: "n", Value: bson.D{{Key: "$gt", Value: 1}}}}`,
+		}},
+		{"Status, -CreatedAt ,Owner.Name unique", IndexMeta{
+			Keys:   []IndexKey{{Field: "Status"}, {Field: "CreatedAt", Desc: true}, {Field: "Owner.Name"}},
+			Unique: true,
+		}},
+		{`Name partial={"tags": {"$in": ["a", 1.5, true, null]}, "n": {"$lt": 5000000000}}`, IndexMeta{
+			Keys:    []IndexKey{{Field: "Name"}},
+			Partial: `bson.D{{Key: "tags", Value: bson.D{{Key: "$in", Value: bson.A{"a", float64(1.5), true, nil}}}}, {Key: "n", Value: bson.D{{Key: "$lt", Value: 5000000000}}}}`,
+		}},
+	}
+	for _, tt := range tests {
+		got, err := parseIndexDirective(tt.value)
+		if err != nil {
+			t.Errorf("parseIndexDirective(%q) error = %v", tt.value, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseIndexDirective(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseIndexTag(t *testing.T) {
+	zero := int32(0)
+	got, err := parseIndexTag("ExpiresAt", `index,desc,ttl=0s,partial={"a": 1, "b": 2}`)
+	if err != nil {
+		t.Fatalf("parseIndexTag() error = %v", err)
+	}
+	want := IndexMeta{Keys: []IndexKey{{Field: "ExpiresAt", Desc: true}}, TTL: &zero, Partial: `bson.D{{Key: "a", Value: 1}, {Key: "b", Value: 2}}`}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseIndexTag() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseIndex_Invalid(t *testing.T) {
+	for _, value := range []string{
+		"Name,",
+		"Name uniq",
+		"Name unique=true",
+		"Name desc",
+		"Name name=",
+		"Name ttl=1.5s",
+		"Name ttl=-1h",
+		"Name ttl=tomorrow",
+		"Name partial=[1]",
+		"Name partial={",
+		"Name, ,Score",
+		`Name partial={"a": 1} {"b": 2}`,
+		`Name partial={"at": {"$gt": {"$date": "2024-01-01T00:00:00Z"}}}`,
+		`Name partial={"n": 1e999}`,
+	} {
+		if _, err := parseIndexDirective(value); err == nil {
+			t.Errorf("parseIndexDirective(%q) expected error, got nil", value)
+		}
+	}
+	for _, tag := range []string{"", "idx", "index,,unique", "index,ttl"} {
+		if _, err := parseIndexTag("Name", tag); err == nil {
+			t.Errorf("parseIndexTag(%q) expected error, got nil", tag)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package generator
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// Field kinds name the generated type of a field and so the operators it
+// offers: Like on strings, IsTrue on booleans, ordering and Between on numbers
+// and times. Types without a more specific kind get the plain Field.
+const (
+	KindField  = "Field"
+	KindString = "StringField"
+	KindBool   = "BoolField"
+	KindNumber = "NumberField"
+	KindTime   = "TimeField"
+	KindSlice  = "SliceField"
+)
+
+// basicKinds maps the predeclared types to their kind.
+var basicKinds = map[string]string{
+	"string":     KindString,
+	"bool":       KindBool,
+	"int":        KindNumber,
+	"int8":       KindNumber,
+	"int16":      KindNumber,
+	"int32":      KindNumber,
+	"int64":      KindNumber,
+	"uint":       KindNumber,
+	"uint8":      KindNumber,
+	"uint16":     KindNumber,
+	"uint32":     KindNumber,
+	"uint64":     KindNumber,
+	"uintptr":    KindNumber,
+	"float32":    KindNumber,
+	"float64":    KindNumber,
+	"byte":       KindNumber,
+	"rune":       KindNumber,
+	"complex64":  KindField,
+	"complex128": KindField,
+}
+
+// wellKnownKinds maps struct types of common packages that the database
+// drivers store as scalars, keyed by import path and type name.
+var wellKnownKinds = map[string]string{
+	"time.Time":                KindTime,
+	"database/sql.NullTime":    KindTime,
+	"gorm.io/gorm.DeletedAt":   KindTime,
+	"database/sql.NullString":  KindString,
+	"database/sql.NullBool":    KindBool,
+	"database/sql.NullByte":    KindNumber,
+	"database/sql.NullInt16":   KindNumber,
+	"database/sql.NullInt32":   KindNumber,
+	"database/sql.NullInt64":   KindNumber,
+	"database/sql.NullFloat64": KindNumber,
+}
+
+// typeString is the Go type of a field as written in its declaring file.
+func typeString(expr ast.Expr) string {
+	return types.ExprString(expr)
+}
+
+// fieldKind classifies the type of a field. Pointers are looked through,
+// []byte stays a plain Field since drivers store it as a blob, and named types
+// are resolved to their underlying type in the declaring package, loading
+// other packages through resolver when needed.
+func fieldKind(expr ast.Expr, imports map[string]string, scope *pkgScope, resolver *pkgResolver) string {
+	return resolveKind(expr, imports, scope, resolver, 0)
+}
+
+// maxKindDepth bounds the chain of named types followed by resolveKind.
+const maxKindDepth = 8
+
+func resolveKind(expr ast.Expr, imports map[string]string, scope *pkgScope, resolver *pkgResolver, depth int) string {
+	if depth > maxKindDepth {
+		return KindField
+	}
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return resolveKind(t.X, imports, scope, resolver, depth+1)
+	case *ast.ParenExpr:
+		return resolveKind(t.X, imports, scope, resolver, depth+1)
+	case *ast.ArrayType:
+		if t.Len != nil {
+			return KindField
+		}
+		if elt, ok := t.Elt.(*ast.Ident); ok && (elt.Name == "byte" || elt.Name == "uint8") {
+			return KindField
+		}
+		return KindSlice
+	case *ast.Ident:
+		if scope != nil {
+			if decl, ok := scope.named[t.Name]; ok {
+				return resolveKind(decl.typ, decl.imports, scope, resolver, depth+1)
+			}
+			if _, ok := scope.structs[t.Name]; ok {
+				return KindField
+			}
+		}
+		if kind, ok := basicKinds[t.Name]; ok {
+			return kind
+		}
+	case *ast.SelectorExpr:
+		pkg, ok := t.X.(*ast.Ident)
+		if !ok {
+			return KindField
+		}
+		importPath, ok := imports[pkg.Name]
+		if !ok {
+			return KindField
+		}
+		if kind, ok := wellKnownKinds[importPath+"."+t.Sel.Name]; ok {
+			return kind
+		}
+		if resolver == nil {
+			return KindField
+		}
+		extScope, err := resolver.loadPackage(importPath)
+		if err != nil {
+			return KindField
+		}
+		if decl, ok := extScope.named[t.Sel.Name]; ok {
+			return resolveKind(decl.typ, decl.imports, extScope, resolver, depth+1)
+		}
+	}
+	return KindField
+}
+
+// typeArg returns the type argument of the generated Field[T] for a field of
+// type expr, together with the imports it needs, keyed by package name.
+// Pointers are looked through so that nullable columns compare against their
+// values. Types that cannot be named from the generated package, such as
+// types declared in the source package itself, give "any".
+func typeArg(expr ast.Expr, imports map[string]string, scope *pkgScope) (string, map[string]string) {
+	needed := make(map[string]string)
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	arg, ok := qualifiedType(expr, imports, scope, needed)
+	if !ok {
+		return "any", nil
+	}
+	if len(needed) == 0 {
+		needed = nil
+	}
+	return arg, needed
+}
+
+// qualifiedType writes expr as it must be spelled in the generated package,
+// recording the imports it uses in needed.
+func qualifiedType(expr ast.Expr, imports map[string]string, scope *pkgScope, needed map[string]string) (string, bool) {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if scope != nil {
+			if _, ok := scope.named[t.Name]; ok {
+				return "", false
+			}
+			if _, ok := scope.structs[t.Name]; ok {
+				return "", false
+			}
+		}
+		if _, ok := basicKinds[t.Name]; ok || t.Name == "any" || t.Name == "error" {
+			return t.Name, true
+		}
+	case *ast.SelectorExpr:
+		pkg, ok := t.X.(*ast.Ident)
+		if !ok || !t.Sel.IsExported() {
+			return "", false
+		}
+		importPath, ok := imports[pkg.Name]
+		if !ok {
+			return "", false
+		}
+		needed[pkg.Name] = importPath
+		return pkg.Name + "." + t.Sel.Name, true
+	case *ast.StarExpr:
+		elem, ok := qualifiedType(t.X, imports, scope, needed)
+		return "*" + elem, ok
+	case *ast.ArrayType:
+		elem, ok := qualifiedType(t.Elt, imports, scope, needed)
+		if !ok {
+			return "", false
+		}
+		if t.Len == nil {
+			return "[]" + elem, true
+		}
+		if lit, isLit := t.Len.(*ast.BasicLit); isLit {
+			return "[" + lit.Value + "]" + elem, true
+		}
+	case *ast.MapType:
+		key, ok := qualifiedType(t.Key, imports, scope, needed)
+		if !ok {
+			return "", false
+		}
+		value, ok := qualifiedType(t.Value, imports, scope, needed)
+		if !ok {
+			return "", false
+		}
+		return "map[" + key + "]" + value, true
+	case *ast.InterfaceType:
+		if t.Methods == nil || len(t.Methods.List) == 0 {
+			return "any", true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,481 @@
+package generator
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"go/build"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// modFile is the subset of a go.mod or go.work file needed to locate packages.
+type modFile struct {
+	dir      string            // directory containing the file
+	module   string            // module directive (go.mod only)
+	requires map[string]string // module path -> version
+	replaces []modReplace
+	uses     []string // use directives (go.work only), relative to dir
+}
+
+// modReplace is a replace directive. NewVersion is empty for local directory replacements.
+type modReplace struct {
+	OldPath    string
+	OldVersion string
+	NewPath    string
+	NewVersion string
+}
+
+// parseModFile reads the directives of a go.mod or go.work file.
+func parseModFile(path string) (*modFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	mf := &modFile{dir: filepath.Dir(path), requires: make(map[string]string)}
+	var block string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields, err := modFields(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		verb := block
+		switch {
+		case block != "" && fields[0] == ")":
+			block = ""
+			continue
+		case block == "" && len(fields) == 2 && fields[1] == "(":
+			block = fields[0]
+			continue
+		case block == "":
+			verb, fields = fields[0], fields[1:]
+		}
+		switch verb {
+		case "module":
+			if len(fields) > 0 {
+				mf.module = fields[0]
+			}
+		case "require":
+			if len(fields) >= 2 {
+				mf.requires[fields[0]] = fields[1]
+			}
+		case "use":
+			if len(fields) > 0 {
+				mf.uses = append(mf.uses, fields[0])
+			}
+		case "replace":
+			r, err := parseReplace(fields)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			mf.replaces = append(mf.replaces, r)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return mf, nil
+}
+
+// modFields splits a go.mod line into tokens, unquoting quoted ones.
+func modFields(line string) ([]string, error) {
+	var fields []string
+	for line = strings.TrimSpace(line); line != ""; line = strings.TrimSpace(line) {
+		switch line[0] {
+		case '"', '`':
+			quoted, err := strconv.QuotedPrefix(line)
+			if err != nil {
+				return nil, err
+			}
+			field, _ := strconv.Unquote(quoted)
+			fields = append(fields, field)
+			line = line[len(quoted):]
+		default:
+			end := strings.IndexFunc(line, unicode.IsSpace)
+			if end < 0 {
+				end = len(line)
+			}
+			fields = append(fields, line[:end])
+			line = line[end:]
+		}
+	}
+	return fields, nil
+}
+
+// parseReplace parses "old [v] => new [v]".
+func parseReplace(fields []string) (modReplace, error) {
+	arrow := -1
+	for i, f := range fields {
+		if f == "=>" {
+			arrow = i
+		}
+	}
+	if arrow < 1 || arrow > 2 || len(fields)-arrow-1 < 1 || len(fields)-arrow-1 > 2 {
+		return modReplace{}, fmt.Errorf("malformed replace directive %q", strings.Join(fields, " "))
+	}
+	r := modReplace{OldPath: fields[0], NewPath: fields[arrow+1]}
+	if arrow == 2 {
+		r.OldVersion = fields[1]
+	}
+	if len(fields) == arrow+3 {
+		r.NewVersion = fields[arrow+2]
+	}
+	return r, nil
+}
+
+// findModuleInfo walks up from the source file to find go.mod and reads the module path.
+func findModuleInfo(sourceFile string) (modulePath, moduleRoot string, err error) {
+	absFile, err := filepath.Abs(sourceFile)
+	if err != nil {
+		return "", "", err
+	}
+	dir := filepath.Dir(absFile)
+	for {
+		gomod := filepath.Join(dir, "go.mod")
+		if _, statErr := os.Stat(gomod); statErr == nil {
+			mf, err := parseModFile(gomod)
+			if err != nil {
+				return "", "", err
+			}
+			if mf.module == "" {
+				return "", "", fmt.Errorf("module directive not found in %s", gomod)
+			}
+			return mf.module, dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return "", "", fmt.Errorf("go.mod not found")
+}
+
+// findWorkFile locates the go.work governing moduleRoot, honoring GOWORK.
+func findWorkFile(moduleRoot string) string {
+	switch gowork := os.Getenv("GOWORK"); gowork {
+	case "off":
+		return ""
+	case "":
+	default:
+		return gowork
+	}
+	for dir := moduleRoot; ; {
+		work := filepath.Join(dir, "go.work")
+		if _, err := os.Stat(work); err == nil {
+			return work
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// moduleGraph maps import paths to directories the way the go command does
+// for the main module: workspace modules first, then vendor/, then the module
+// cache, with replace directives applied.
+type moduleGraph struct {
+	modules   map[string]string // module path -> directory, for main and workspace modules
+	requires  map[string]string // module path -> selected version
+	replaces  []dirReplace
+	vendorDir string // non-empty when packages are loaded from vendor/
+	modCache  string
+	goroot    string
+}
+
+// dirReplace is a replace directive with local paths made absolute.
+type dirReplace struct {
+	modReplace
+	dir string // directory of the file declaring the directive
+}
+
+// loadModuleGraph builds the module graph of the module containing sourceFile.
+func loadModuleGraph(sourceFile string) (*moduleGraph, error) {
+	_, moduleRoot, err := findModuleInfo(sourceFile)
+	if err != nil {
+		return nil, err
+	}
+	g := &moduleGraph{
+		modules:  make(map[string]string),
+		requires: make(map[string]string),
+		modCache: moduleCacheDir(),
+		goroot:   build.Default.GOROOT,
+	}
+
+	roots := []string{moduleRoot}
+	vendorRoot := moduleRoot
+	if workPath := findWorkFile(moduleRoot); workPath != "" {
+		work, err := parseModFile(workPath)
+		if err != nil {
+			return nil, err
+		}
+		// go.work replaces take precedence over the ones of its modules
+		for _, r := range work.replaces {
+			g.replaces = append(g.replaces, dirReplace{r, work.dir})
+		}
+		roots = roots[:0]
+		for _, use := range work.uses {
+			roots = append(roots, filepath.Join(work.dir, use))
+		}
+		vendorRoot = work.dir
+	}
+	for _, root := range roots {
+		mf, err := parseModFile(filepath.Join(root, "go.mod"))
+		if err != nil {
+			return nil, err
+		}
+		g.modules[mf.module] = root
+		// Like minimal version selection, the highest required version wins
+		for path, version := range mf.requires {
+			if current, ok := g.requires[path]; !ok || compareVersions(version, current) > 0 {
+				g.requires[path] = version
+			}
+		}
+		for _, r := range mf.replaces {
+			g.replaces = append(g.replaces, dirReplace{r, mf.dir})
+		}
+	}
+	if vendorEnabled(vendorRoot) {
+		g.vendorDir = filepath.Join(vendorRoot, "vendor")
+	}
+	return g, nil
+}
+
+// compareVersions compares two module versions by semantic version
+// precedence, like golang.org/x/mod/semver.Compare: build metadata such as
+// +incompatible is ignored and prereleases, pseudo-versions included, sort
+// before their release. Invalid versions sort before valid ones.
+func compareVersions(a, b string) int {
+	va, okA := parseVersion(a)
+	vb, okB := parseVersion(b)
+	if !okA || !okB {
+		return boolCompare(okA, okB)
+	}
+	for i := range va.core {
+		if c := compareNumeric(va.core[i], vb.core[i]); c != 0 {
+			return c
+		}
+	}
+	return comparePrerelease(va.prerelease, vb.prerelease)
+}
+
+type semver struct {
+	core       [3]string // major, minor and patch, without leading zeros
+	prerelease string    // without the leading "-"
+}
+
+// parseVersion parses vMAJOR[.MINOR[.PATCH]][-PRERELEASE][+BUILD].
+func parseVersion(v string) (semver, bool) {
+	var sv semver
+	rest, ok := strings.CutPrefix(v, "v")
+	if !ok {
+		return sv, false
+	}
+	rest, _, _ = strings.Cut(rest, "+")
+	rest, sv.prerelease, ok = strings.Cut(rest, "-")
+	if ok && sv.prerelease == "" {
+		return sv, false
+	}
+	parts := strings.Split(rest, ".")
+	if len(parts) > 3 {
+		return sv, false
+	}
+	sv.core = [3]string{"0", "0", "0"}
+	for i, p := range parts {
+		if !isNumeric(p) || len(p) > 1 && p[0] == '0' {
+			return sv, false
+		}
+		sv.core[i] = p
+	}
+	return sv, true
+}
+
+// comparePrerelease compares the dot-separated identifiers of two
+// prereleases; a release, without one, sorts after any prerelease.
+func comparePrerelease(a, b string) int {
+	if a == "" || b == "" {
+		return boolCompare(a == "", b == "")
+	}
+	ia, ib := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(ia) && i < len(ib); i++ {
+		numA, numB := isNumeric(ia[i]), isNumeric(ib[i])
+		var c int
+		switch {
+		case numA && numB:
+			c = compareNumeric(ia[i], ib[i])
+		case numA || numB:
+			// numeric identifiers sort before alphanumeric ones
+			c = boolCompare(numB, numA)
+		default:
+			c = strings.Compare(ia[i], ib[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(ia), len(ib))
+}
+
+// compareNumeric compares two decimal numbers without leading zeros.
+func compareNumeric(a, b string) int {
+	if len(a) != len(b) {
+		return cmp.Compare(len(a), len(b))
+	}
+	return strings.Compare(a, b)
+}
+
+func isNumeric(s string) bool {
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
+
+// boolCompare orders false before true.
+func boolCompare(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	}
+	return -1
+}
+
+// vendorEnabled reports whether the go command would build from root/vendor.
+func vendorEnabled(root string) bool {
+	if _, err := os.Stat(filepath.Join(root, "vendor", "modules.txt")); err != nil {
+		return false
+	}
+	for _, flag := range strings.Fields(os.Getenv("GOFLAGS")) {
+		if flag == "-mod=mod" || flag == "-mod=readonly" {
+			return false
+		}
+	}
+	return true
+}
+
+// moduleCacheDir returns GOMODCACHE, defaulting to GOPATH/pkg/mod.
+func moduleCacheDir() string {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+	gopath := filepath.SplitList(build.Default.GOPATH)
+	if len(gopath) == 0 {
+		return ""
+	}
+	return filepath.Join(gopath[0], "pkg", "mod")
+}
+
+// dir returns the directory holding the package importPath.
+func (g *moduleGraph) dir(importPath string) (string, error) {
+	if mod, rest, ok := longestModule(importPath, g.modules); ok {
+		return filepath.Join(g.modules[mod], rest), nil
+	}
+	if isStdlib(importPath) {
+		return filepath.Join(g.goroot, "src", importPath), nil
+	}
+	if g.vendorDir != "" {
+		dir := filepath.Join(g.vendorDir, filepath.FromSlash(importPath))
+		if _, err := os.Stat(dir); err != nil {
+			return "", fmt.Errorf("package %s not found in %s", importPath, g.vendorDir)
+		}
+		return dir, nil
+	}
+
+	candidates := make(map[string]string, len(g.requires))
+	for path, version := range g.requires {
+		candidates[path] = version
+	}
+	for _, r := range g.replaces {
+		if r.OldVersion == "" {
+			if _, ok := candidates[r.OldPath]; !ok {
+				candidates[r.OldPath] = ""
+			}
+		}
+	}
+	mod, rest, ok := longestModule(importPath, candidates)
+	if !ok {
+		return "", fmt.Errorf("package %s is not provided by any required module", importPath)
+	}
+	version := candidates[mod]
+	for _, r := range g.replaces {
+		if r.OldPath != mod || (r.OldVersion != "" && r.OldVersion != version) {
+			continue
+		}
+		if r.NewVersion == "" {
+			// Local directory replacement
+			dir := r.NewPath
+			if !filepath.IsAbs(dir) {
+				dir = filepath.Join(r.dir, dir)
+			}
+			return filepath.Join(dir, rest), nil
+		}
+		mod, version = r.NewPath, r.NewVersion
+		break
+	}
+	return g.cacheDir(mod, version, rest)
+}
+
+// cacheDir returns the module cache directory of a package inside mod@version.
+func (g *moduleGraph) cacheDir(mod, version, rest string) (string, error) {
+	if version == "" {
+		return "", fmt.Errorf("module %s has no version", mod)
+	}
+	root := filepath.Join(g.modCache, escapeModulePath(mod)+"@"+escapeModulePath(version))
+	if _, err := os.Stat(root); err != nil {
+		return "", fmt.Errorf("module %s@%s not found in module cache %s (run go mod download)", mod, version, g.modCache)
+	}
+	return filepath.Join(root, rest), nil
+}
+
+// longestModule finds the module in mods that provides importPath and returns
+// the package directory relative to the module root.
+func longestModule[V any](importPath string, mods map[string]V) (mod, rest string, ok bool) {
+	for path := range mods {
+		if path != importPath && !strings.HasPrefix(importPath, path+"/") {
+			continue
+		}
+		if len(path) > len(mod) {
+			mod, ok = path, true
+		}
+	}
+	if ok {
+		rest = filepath.FromSlash(strings.TrimPrefix(strings.TrimPrefix(importPath, mod), "/"))
+	}
+	return mod, rest, ok
+}
+
+// isStdlib reports whether importPath belongs to the standard library, whose
+// first path element never contains a dot.
+func isStdlib(importPath string) bool {
+	first, _, _ := strings.Cut(importPath, "/")
+	return !strings.Contains(first, ".")
+}
+
+// escapeModulePath applies the module cache case encoding: every upper-case
+// letter becomes '!' followed by its lower-case form.
+func escapeModulePath(path string) string {
+	var b strings.Builder
+	for _, r := range path {
+		if unicode.IsUpper(r) {
+			b.WriteByte('!')
+			b.WriteRune(unicode.ToLower(r))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,277 @@
+package generator
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// ---- parseModFile ---------------------------------------------------------------
+
+func TestParseModFile(t *testing.T) {
+	dir := t.TempDir()
+	gomod := filepath.Join(dir, "go.mod")
+	mustWriteFile(t, gomod, `module example.com/app // main module
+
+go 1.22
+
+require example.com/single v1.0.0
+
+require (
+	github.com/Shared/Model v1.2.3
+	example.com/indirect v0.1.0 // indirect
+)
+
+replace example.com/single => ../single
+
+replace (
+	example.com/forked v1.0.0 => example.com/fork v1.0.1
+)
+`)
+
+	mf, err := parseModFile(gomod)
+	if err != nil {
+		t.Fatalf("parseModFile() error = %v", err)
+	}
+	if mf.module != "example.com/app" {
+		t.Errorf("module = %q, want example.com/app", mf.module)
+	}
+	wantRequires := map[string]string{
+		"example.com/single":      "v1.0.0",
+		"github.com/Shared/Model": "v1.2.3",
+		"example.com/indirect":    "v0.1.0",
+	}
+	if !reflect.DeepEqual(mf.requires, wantRequires) {
+		t.Errorf("requires = %v, want %v", mf.requires, wantRequires)
+	}
+	wantReplaces := []modReplace{
+		{OldPath: "example.com/single", NewPath: "../single"},
+		{OldPath: "example.com/forked", OldVersion: "v1.0.0", NewPath: "example.com/fork", NewVersion: "v1.0.1"},
+	}
+	if !reflect.DeepEqual(mf.replaces, wantReplaces) {
+		t.Errorf("replaces = %+v, want %+v", mf.replaces, wantReplaces)
+	}
+}
+
+func TestEscapeModulePath(t *testing.T) {
+	if got := escapeModulePath("github.com/Shared/Model"); got != "github.com/!shared/!model" {
+		t.Errorf("escapeModulePath() = %q", got)
+	}
+}
+
+// ---- moduleGraph.dir ------------------------------------------------------------
+
+func TestModuleGraphDir(t *testing.T) {
+	root := t.TempDir()
+	cache := filepath.Join(root, "modcache")
+	t.Setenv("GOMODCACHE", cache)
+	t.Setenv("GOWORK", "off")
+	t.Setenv("GOFLAGS", "")
+
+	app := filepath.Join(root, "app")
+	mustMkdir(t, filepath.Join(app, "internal", "db"))
+	mustMkdir(t, filepath.Join(cache, "github.com", "!shared", "!model@v1.2.3", "base"))
+	mustMkdir(t, filepath.Join(cache, "example.com", "fork@v1.0.1", "pkg"))
+	mustWriteFile(t, filepath.Join(app, "go.mod"), `module example.com/app
+
+require (
+	github.com/Shared/Model v1.2.3
+	example.com/forked v1.0.0
+	example.com/local v0.0.0
+	example.com/missing v1.0.0
+)
+
+replace example.com/forked => example.com/fork v1.0.1
+
+replace example.com/local => ../local
+`)
+
+	g, err := loadModuleGraph(filepath.Join(app, "main.go"))
+	if err != nil {
+		t.Fatalf("loadModuleGraph() error = %v", err)
+	}
+	tests := []struct {
+		importPath string
+		want       string
+	}{
+		{"example.com/app/internal/db", filepath.Join(app, "internal", "db")},
+		{"github.com/Shared/Model/base", filepath.Join(cache, "github.com", "!shared", "!model@v1.2.3", "base")},
+		{"example.com/forked/pkg", filepath.Join(cache, "example.com", "fork@v1.0.1", "pkg")},
+		{"example.com/local/model", filepath.Join(root, "local", "model")},
+		{"time", filepath.Join(g.goroot, "src", "time")},
+	}
+	for _, tt := range tests {
+		got, err := g.dir(tt.importPath)
+		if err != nil {
+			t.Errorf("dir(%q) error = %v", tt.importPath, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("dir(%q) = %q, want %q", tt.importPath, got, tt.want)
+		}
+	}
+
+	for _, importPath := range []string{"example.com/missing/pkg", "example.com/unknown/pkg"} {
+		if _, err := g.dir(importPath); err == nil {
+			t.Errorf("dir(%q) expected error, got nil", importPath)
+		}
+	}
+}
+
+func TestModuleGraphDir_Vendor(t *testing.T) {
+	app := t.TempDir()
+	t.Setenv("GOWORK", "off")
+	t.Setenv("GOFLAGS", "")
+	mustMkdir(t, filepath.Join(app, "vendor", "github.com", "shared", "model"))
+	mustWriteFile(t, filepath.Join(app, "vendor", "modules.txt"), "# github.com/shared/model v1.0.0\n")
+	mustWriteFile(t, filepath.Join(app, "go.mod"), "module example.com/app\n\nrequire github.com/shared/model v1.0.0\n")
+
+	g, err := loadModuleGraph(filepath.Join(app, "main.go"))
+	if err != nil {
+		t.Fatalf("loadModuleGraph() error = %v", err)
+	}
+	got, err := g.dir("github.com/shared/model")
+	if err != nil {
+		t.Fatalf("dir() error = %v", err)
+	}
+	if want := filepath.Join(app, "vendor", "github.com", "shared", "model"); got != want {
+		t.Errorf("dir() = %q, want %q", got, want)
+	}
+}
+
+func TestModuleGraphDir_Workspace(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("GOWORK", "")
+	t.Setenv("GOFLAGS", "")
+	mustMkdir(t, filepath.Join(root, "app"))
+	mustMkdir(t, filepath.Join(root, "platform", "model"))
+	mustWriteFile(t, filepath.Join(root, "go.work"), "go 1.22\n\nuse (\n\t./app\n\t./platform\n)\n")
+	mustWriteFile(t, filepath.Join(root, "app", "go.mod"), "module example.com/app\n\nrequire (\n\texample.com/platform v1.0.0\n\texample.com/lib v1.2.0\n\texample.com/util v0.3.0\n)\n")
+	mustWriteFile(t, filepath.Join(root, "platform", "go.mod"), "module example.com/platform\n\nrequire (\n\texample.com/lib v1.10.0\n\texample.com/util v0.3.0-rc.1\n)\n")
+
+	g, err := loadModuleGraph(filepath.Join(root, "app", "main.go"))
+	if err != nil {
+		t.Fatalf("loadModuleGraph() error = %v", err)
+	}
+	got, err := g.dir("example.com/platform/model")
+	if err != nil {
+		t.Fatalf("dir() error = %v", err)
+	}
+	if want := filepath.Join(root, "platform", "model"); got != want {
+		t.Errorf("dir() = %q, want %q", got, want)
+	}
+	// the modules of the workspace require different versions: the highest wins
+	for path, want := range map[string]string{"example.com/lib": "v1.10.0", "example.com/util": "v0.3.0"} {
+		if got := g.requires[path]; got != want {
+			t.Errorf("requires[%s] = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v1.2.0", "v1.10.0", -1},
+		{"v1.10.0", "v1.2.0", 1},
+		{"v1.2.3", "v1.2.3", 0},
+		{"v2.0.0+incompatible", "v2.0.0", 0},
+		{"v1.0.0-rc.1", "v1.0.0", -1},
+		{"v1.0.0-alpha", "v1.0.0-alpha.1", -1},
+		{"v1.0.0-alpha.2", "v1.0.0-alpha.10", -1},
+		{"v1.0.0-1", "v1.0.0-alpha", -1},
+		{"v0.0.0-20240101000000-abcdefabcdef", "v0.0.0-20230101000000-abcdefabcdef", 1},
+		{"v1.2", "v1.2.0", 0},
+		{"bad", "v0.0.1", -1},
+		{"v01.0.0", "v0.0.1", -1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// ---- embedded structs from other modules ------------------------------------------
+
+func TestParseFile_EmbeddedFromModuleCache(t *testing.T) {
+	root := t.TempDir()
+	cache := filepath.Join(root, "modcache")
+	t.Setenv("GOMODCACHE", cache)
+	t.Setenv("GOWORK", "off")
+	t.Setenv("GOFLAGS", "")
+
+	modelDir := filepath.Join(cache, "example.com", "platform@v1.0.0", "model")
+	mustMkdir(t, modelDir)
+	mustWriteFile(t, filepath.Join(modelDir, "base.go"), `package model
+
+import "example.com/platform/audit"
+
+type Base struct {
+	ID          uint `+"`gorm:\"column:id\"`"+`
+	audit.Trail `+"`gorm:\"embedded\"`"+`
+}
+`)
+	auditDir := filepath.Join(cache, "example.com", "platform@v1.0.0", "audit")
+	mustMkdir(t, auditDir)
+	mustWriteFile(t, filepath.Join(auditDir, "trail.go"), `package audit
+
+type Trail struct {
+	CreatedBy string `+"`gorm:\"column:created_by\"`"+`
+}
+`)
+
+	app := filepath.Join(root, "app")
+	mustMkdir(t, app)
+	mustWriteFile(t, filepath.Join(app, "go.mod"), "module example.com/app\n\nrequire example.com/platform v1.0.0\n")
+	src := filepath.Join(app, "models.go")
+	mustWriteFile(t, src, `package app
+
+import "example.com/platform/model"
+
+type User struct {
+	model.Base `+"`gorm:\"embedded\"`"+`
+	Name string `+"`gorm:\"column:name\"`"+`
+}
+`)
+
+	structs, _, err := parseFile(src, "gorm")
+	if err != nil {
+		t.Fatalf("parseFile() error = %v", err)
+	}
+	want := []FieldMeta{
+		{FieldName: "ID", TagName: "id"},
+		{FieldName: "CreatedBy", TagName: "created_by"},
+		{FieldName: "Name", TagName: "name"},
+	}
+	if len(structs) != 1 || !reflect.DeepEqual(namesOnly(structs[0].Fields), want) {
+		t.Errorf("structs = %+v, want User with %+v", structs, want)
+	}
+}
+
+func TestParseFile_UnresolvableEmbeddedFails(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("GOMODCACHE", filepath.Join(root, "modcache"))
+	t.Setenv("GOWORK", "off")
+	t.Setenv("GOFLAGS", "")
+	mustWriteFile(t, filepath.Join(root, "go.mod"), "module example.com/app\n\nrequire example.com/platform v1.0.0\n")
+	src := filepath.Join(root, "models.go")
+	mustWriteFile(t, src, `package app
+
+import "example.com/platform/model"
+
+type User struct {
+	model.Base `+"`gorm:\"embedded\"`"+`
+}
+`)
+
+	_, _, err := parseFile(src, "gorm")
+	if err == nil {
+		t.Fatal("expected error for unresolvable embedded struct, got nil")
+	}
+	if !strings.Contains(err.Error(), "model.Base") {
+		t.Errorf("error %q should name the embedded struct", err)
+	}
+}
@@ -0,0 +1,56 @@
+package generator
+
+const mongoIndexTemplate = `// Code generated by metamodel. DO NOT EDIT.
+
+package [[.PackageName]]
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// MgoIndexView is the part of mongo.IndexView that EnsureIndexesWith uses, so
+// that indexes can be created through a fake in tests.
+type MgoIndexView interface {
+	CreateMany(ctx context.Context, models []mongo.IndexModel, opts ...options.Lister[options.CreateIndexesOptions]) ([]string, error)
+}
+
+// mgoCollectionIndexes are the indexes declared on the struct stored in a collection.
+type mgoCollectionIndexes struct {
+	collection string
+	models     func() []mongo.IndexModel
+}
+
+// mgoIndexes lists the collections whose structs declare indexes. Each
+// metamodel file registers its own, so that files generated separately into
+// this package all take part in EnsureIndexes.
+var mgoIndexes []mgoCollectionIndexes
+
+func registerMgoIndexes(collection string, models func() []mongo.IndexModel) {
+	mgoIndexes = append(mgoIndexes, mgoCollectionIndexes{collection: collection, models: models})
+}
+
+// EnsureIndexes creates in db the indexes declared on the structs of this
+// package, by //metamodel:index directives and metamodel:"index" tags.
+// Indexes that already exist are left as they are; one that exists with other
+// options makes it fail.
+func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	return EnsureIndexesWith(ctx, func(collection string) MgoIndexView {
+		return db.Collection(collection).Indexes()
+	})
+}
+
+// EnsureIndexesWith creates the indexes declared on the structs of this
+// package through the index view returned for each collection.
+func EnsureIndexesWith(ctx context.Context, view func(collection string) MgoIndexView) error {
+	for _, c := range mgoIndexes {
+		if _, err := view(c.collection).CreateMany(ctx, c.models()); err != nil {
+			return fmt.Errorf("create indexes of %s: %w", c.collection, err)
+		}
+	}
+	return nil
+}
+`
@@ -0,0 +1,225 @@
+package generator
+
+const mongoPipelineTemplate = `// Code generated by metamodel. DO NOT EDIT.
+
+package [[.PackageName]]
+
+import (
+	"slices"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// MgoFieldRef is a document field an aggregation stage refers to: any field
+// of a metamodel, or a name computed by an earlier stage (see MgoName).
+type MgoFieldRef interface {
+	MgoRef() string
+	mgoKey() string
+}
+
+// MgoRef returns the field path of the field in aggregation expressions: "$field"
+// Example: Scenarios_.Owner.Name.MgoRef() → "$owner.name"
+func (f Field[T]) MgoRef() string {
+	return "$" + f.mgoKey()
+}
+
+// MgoName refers to a field that is not part of a metamodel, such as an
+// accumulator of a $group stage or the array added by $lookup.
+// Example: Lookup(Feature_.TableName, Scenarios_.FeatureName, Feature_.FeatureName, "features").Unwind(MgoName("features"))
+func MgoName(name string) MgoFieldRef {
+	return mgoName(name)
+}
+
+type mgoName string
+
+func (n mgoName) MgoRef() string { return "$" + string(n) }
+func (n mgoName) mgoKey() string { return string(n) }
+
+// MgoPipeline builds an aggregation pipeline stage by stage from metamodel
+// fields:
+//
+//	pipeline := NewMgoPipeline().
+//		Match(Scenarios_.Status.MgoNe("deleted")).
+//		Group(Scenarios_.Status).Count("total").Sum("scenarios", Scenarios_.ScenarioID).
+//		Sort(bson.D{{Key: "total", Value: -1}}).
+//		Build()
+//	cursor, err := coll.Aggregate(ctx, pipeline)
+type MgoPipeline struct {
+	stages mongo.Pipeline
+}
+
+// NewMgoPipeline starts an empty pipeline.
+func NewMgoPipeline() *MgoPipeline {
+	return &MgoPipeline{}
+}
+
+// Stage appends a stage the builder has no method for, such as $facet.
+func (p *MgoPipeline) Stage(stage bson.D) *MgoPipeline {
+	p.stages = append(p.stages, stage)
+	return p
+}
+
+// Match filters the documents: { $match: filter }. Several filters are
+// combined with $and.
+func (p *MgoPipeline) Match(filters ...bson.D) *MgoPipeline {
+	filter := bson.D{}
+	switch len(filters) {
+	case 0:
+	case 1:
+		filter = filters[0]
+	default:
+		filter = MgoAnd(filters...)
+	}
+	return p.Stage(bson.D{{Key: "$match", Value: filter}})
+}
+
+// Group groups the documents by the given fields, counted and summed by the
+// accumulators of the returned stage: { $group: { _id: "$field", ... } }.
+// Several fields make a compound _id, { field: "$field", ... }, with the dots
+// of nested fields replaced by underscores; none groups all documents.
+func (p *MgoPipeline) Group(fields ...MgoFieldRef) *MgoGroupStage {
+	var id any
+	switch len(fields) {
+	case 0:
+	case 1:
+		id = fields[0].MgoRef()
+	default:
+		keys := make(bson.D, len(fields))
+		for i, f := range fields {
+			keys[i] = bson.E{Key: strings.ReplaceAll(f.mgoKey(), ".", "_"), Value: f.MgoRef()}
+		}
+		id = keys
+	}
+	p.Stage(bson.D{{Key: "$group", Value: bson.D{{Key: "_id", Value: id}}}})
+	return &MgoGroupStage{MgoPipeline: p, stage: len(p.stages) - 1}
+}
+
+// Project keeps only the given fields, and _id: { $project: { field: 1, ... } }
+func (p *MgoPipeline) Project(fields ...MgoFieldRef) *MgoPipeline {
+	return p.project(fields, 1)
+}
+
+// Exclude drops the given fields: { $project: { field: 0, ... } }
+func (p *MgoPipeline) Exclude(fields ...MgoFieldRef) *MgoPipeline {
+	return p.project(fields, 0)
+}
+
+func (p *MgoPipeline) project(fields []MgoFieldRef, include int) *MgoPipeline {
+	projection := make(bson.D, len(fields))
+	for i, f := range fields {
+		projection[i] = bson.E{Key: f.mgoKey(), Value: include}
+	}
+	return p.Stage(bson.D{{Key: "$project", Value: projection}})
+}
+
+// Sort orders the documents by the sort documents in turn:
+// { $sort: { field1: 1, field2: -1 } }
+// Example: Sort(Scenarios_.Status.MgoAsc(), bson.D{{Key: "total", Value: -1}})
+func (p *MgoPipeline) Sort(sorts ...bson.D) *MgoPipeline {
+	return p.Stage(bson.D{{Key: "$sort", Value: slices.Concat(sorts...)}})
+}
+
+// Lookup joins the documents of the from collection whose foreignField equals
+// localField, adding them as the array as:
+// { $lookup: { from: from, localField: local, foreignField: foreign, as: as } }
+// Example: Lookup(Feature_.TableName, Scenarios_.FeatureName, Feature_.FeatureName, "features")
+func (p *MgoPipeline) Lookup(from string, localField, foreignField MgoFieldRef, as string) *MgoPipeline {
+	return p.Stage(bson.D{{Key: "$lookup", Value: bson.D{
+		{Key: "from", Value: from},
+		{Key: "localField", Value: localField.mgoKey()},
+		{Key: "foreignField", Value: foreignField.mgoKey()},
+		{Key: "as", Value: as},
+	}}})
+}
+
+// Unwind outputs one document per element of the array field, dropping the
+// documents where it is missing or empty: { $unwind: "$field" }
+func (p *MgoPipeline) Unwind(field MgoFieldRef) *MgoPipeline {
+	return p.Stage(bson.D{{Key: "$unwind", Value: field.MgoRef()}})
+}
+
+// UnwindPreserveEmpty is Unwind keeping the documents without elements:
+// { $unwind: { path: "$field", preserveNullAndEmptyArrays: true } }
+func (p *MgoPipeline) UnwindPreserveEmpty(field MgoFieldRef) *MgoPipeline {
+	return p.Stage(bson.D{{Key: "$unwind", Value: bson.D{
+		{Key: "path", Value: field.MgoRef()},
+		{Key: "preserveNullAndEmptyArrays", Value: true},
+	}}})
+}
+
+// Skip skips the first n documents: { $skip: n }
+func (p *MgoPipeline) Skip(n int64) *MgoPipeline {
+	return p.Stage(bson.D{{Key: "$skip", Value: n}})
+}
+
+// Limit passes the first n documents on: { $limit: n }
+func (p *MgoPipeline) Limit(n int64) *MgoPipeline {
+	return p.Stage(bson.D{{Key: "$limit", Value: n}})
+}
+
+// Build returns the stages, for Collection.Aggregate.
+func (p *MgoPipeline) Build() mongo.Pipeline {
+	return slices.Clone(p.stages)
+}
+
+// MgoGroupStage is a $group stage being given its accumulators. It embeds
+// the pipeline, so the next stages chain on after the accumulators.
+type MgoGroupStage struct {
+	*MgoPipeline
+	stage int
+}
+
+// Count counts the documents of each group: { name: { $sum: 1 } }
+func (g *MgoGroupStage) Count(name string) *MgoGroupStage {
+	return g.accumulate(name, "$sum", 1)
+}
+
+// Sum adds up the field over each group: { name: { $sum: "$field" } }
+func (g *MgoGroupStage) Sum(name string, field MgoFieldRef) *MgoGroupStage {
+	return g.accumulate(name, "$sum", field.MgoRef())
+}
+
+// Avg averages the field over each group: { name: { $avg: "$field" } }
+func (g *MgoGroupStage) Avg(name string, field MgoFieldRef) *MgoGroupStage {
+	return g.accumulate(name, "$avg", field.MgoRef())
+}
+
+// Min takes the smallest value of the field: { name: { $min: "$field" } }
+func (g *MgoGroupStage) Min(name string, field MgoFieldRef) *MgoGroupStage {
+	return g.accumulate(name, "$min", field.MgoRef())
+}
+
+// Max takes the largest value of the field: { name: { $max: "$field" } }
+func (g *MgoGroupStage) Max(name string, field MgoFieldRef) *MgoGroupStage {
+	return g.accumulate(name, "$max", field.MgoRef())
+}
+
+// First takes the field of the first document of each group, in the order of
+// a preceding $sort: { name: { $first: "$field" } }
+func (g *MgoGroupStage) First(name string, field MgoFieldRef) *MgoGroupStage {
+	return g.accumulate(name, "$first", field.MgoRef())
+}
+
+// Last takes the field of the last document of each group: { name: { $last: "$field" } }
+func (g *MgoGroupStage) Last(name string, field MgoFieldRef) *MgoGroupStage {
+	return g.accumulate(name, "$last", field.MgoRef())
+}
+
+// Push collects the field of every document into an array: { name: { $push: "$field" } }
+func (g *MgoGroupStage) Push(name string, field MgoFieldRef) *MgoGroupStage {
+	return g.accumulate(name, "$push", field.MgoRef())
+}
+
+// AddToSet collects the distinct values of the field: { name: { $addToSet: "$field" } }
+func (g *MgoGroupStage) AddToSet(name string, field MgoFieldRef) *MgoGroupStage {
+	return g.accumulate(name, "$addToSet", field.MgoRef())
+}
+
+func (g *MgoGroupStage) accumulate(name, op string, val any) *MgoGroupStage {
+	group := g.stages[g.stage][0].Value.(bson.D)
+	g.stages[g.stage][0].Value = append(group, bson.E{Key: name, Value: bson.D{{Key: op, Value: val}}})
+	return g
+}
+`
@@ -0,0 +1,130 @@
+package generator
+
+const mongoQueryTemplate = `// Code generated by metamodel. DO NOT EDIT.
+
+package [[.PackageName]]
+
+import (
+	"slices"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// MgoQuery bundles the filter, sort, projection, skip and limit of a find.
+// Each method returns a modified copy, so a base query can be shared:
+//
+//	q := NewMgoQuery(Scenarios_.Status.MgoEq("open")).
+//		Sort(Scenarios_.ScenarioID.MgoDesc(), Scenarios_.FeatureName.MgoAsc()).
+//		Include(Scenarios_.Status, Scenarios_.Description).
+//		Skip(20).
+//		Limit(10)
+//	cursor, err := coll.Find(ctx, q.Filter(), q.FindOptions())
+//	err = coll.FindOne(ctx, q.Filter(), q.FindOneOptions()).Decode(&scenario)
+type MgoQuery struct {
+	filters    []bson.D
+	sort       bson.D
+	projection bson.D
+	skip       int64
+	limit      int64
+}
+
+// NewMgoQuery starts a query matching the filters.
+func NewMgoQuery(filters ...bson.D) MgoQuery {
+	return MgoQuery{filters: filters}
+}
+
+// Where adds filters the documents must match as well.
+func (q MgoQuery) Where(filters ...bson.D) MgoQuery {
+	q.filters = slices.Concat(q.filters, filters)
+	return q
+}
+
+// Sort adds the sort documents, in order: Sort(a.MgoAsc(), b.MgoDesc())
+// sorts by a, then by b descending.
+func (q MgoQuery) Sort(sorts ...bson.D) MgoQuery {
+	q.sort = slices.Concat(q.sort, slices.Concat(sorts...))
+	return q
+}
+
+// Include returns only the given fields of the documents, and _id:
+// { field: 1, ... }. MongoDB rejects projections mixing included and
+// excluded fields other than _id.
+func (q MgoQuery) Include(fields ...MgoFieldRef) MgoQuery {
+	return q.project(fields, 1)
+}
+
+// Exclude returns the documents without the given fields: { field: 0, ... }
+func (q MgoQuery) Exclude(fields ...MgoFieldRef) MgoQuery {
+	return q.project(fields, 0)
+}
+
+func (q MgoQuery) project(fields []MgoFieldRef, include int) MgoQuery {
+	projection := slices.Clone(q.projection)
+	for _, f := range fields {
+		projection = append(projection, bson.E{Key: f.mgoKey(), Value: include})
+	}
+	q.projection = projection
+	return q
+}
+
+// Skip skips the first n documents.
+func (q MgoQuery) Skip(n int64) MgoQuery {
+	q.skip = n
+	return q
+}
+
+// Limit returns at most n documents.
+func (q MgoQuery) Limit(n int64) MgoQuery {
+	q.limit = n
+	return q
+}
+
+// Filter returns the filter of the query: the filter itself, the filters
+// combined with $and, or an empty document matching every document.
+func (q MgoQuery) Filter() bson.D {
+	switch len(q.filters) {
+	case 0:
+		return bson.D{}
+	case 1:
+		return q.filters[0]
+	default:
+		return MgoAnd(q.filters...)
+	}
+}
+
+// FindOptions returns the sort, projection, skip and limit as the options of
+// Collection.Find. Options left unset are not set.
+func (q MgoQuery) FindOptions() *options.FindOptionsBuilder {
+	opts := options.Find()
+	if len(q.sort) > 0 {
+		opts.SetSort(q.sort)
+	}
+	if len(q.projection) > 0 {
+		opts.SetProjection(q.projection)
+	}
+	if q.skip != 0 {
+		opts.SetSkip(q.skip)
+	}
+	if q.limit != 0 {
+		opts.SetLimit(q.limit)
+	}
+	return opts
+}
+
+// FindOneOptions returns the sort, projection and skip as the options of
+// Collection.FindOne, which ignores the limit.
+func (q MgoQuery) FindOneOptions() *options.FindOneOptionsBuilder {
+	opts := options.FindOne()
+	if len(q.sort) > 0 {
+		opts.SetSort(q.sort)
+	}
+	if len(q.projection) > 0 {
+		opts.SetProjection(q.projection)
+	}
+	if q.skip != 0 {
+		opts.SetSkip(q.skip)
+	}
+	return opts
+}
+`
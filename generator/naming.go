@@ -0,0 +1,129 @@
+package generator
+
+import (
+	"regexp"
+	"strings"
+)
+
+// NamingStrategy mirrors the table options of GORM's schema.NamingStrategy so
+// that default table names of gorm structs match the ones GORM queries.
+type NamingStrategy struct {
+	TablePrefix   string // prepended to every derived table name, e.g. "app_"
+	SingularTable bool   // do not pluralize derived table names
+}
+
+// TableName derives the table name of a struct: snake case, pluralized unless
+// SingularTable is set, prefixed with TablePrefix.
+func (ns NamingStrategy) TableName(structName string) string {
+	if ns.SingularTable {
+		return ns.TablePrefix + gormDBName(structName)
+	}
+	return ns.TablePrefix + pluralize(gormDBName(structName))
+}
+
+// commonInitialisms are the initialisms GORM keeps together when converting
+// names, so "UserID" becomes "user_id" rather than "user_i_d".
+var commonInitialisms = []string{"API", "ASCII", "CPU", "CSS", "DNS", "EOF", "GUID", "HTML", "HTTP", "HTTPS", "ID", "IP", "JSON", "LHS", "QPS", "RAM", "RHS", "RPC", "SLA", "SMTP", "SSH", "TLS", "TTL", "UID", "UI", "UUID", "URI", "URL", "UTF8", "VM", "XML", "XSRF", "XSS"}
+
+var commonInitialismsReplacer = func() *strings.Replacer {
+	pairs := make([]string, 0, 2*len(commonInitialisms))
+	for _, initialism := range commonInitialisms {
+		pairs = append(pairs, initialism, initialism[:1]+strings.ToLower(initialism[1:]))
+	}
+	return strings.NewReplacer(pairs...)
+}()
+
+// gormDBName converts a Go identifier to a column or table name exactly like
+// GORM's NamingStrategy.toDBName.
+func gormDBName(name string) string {
+	if name == "" {
+		return ""
+	}
+	var (
+		value                          = commonInitialismsReplacer.Replace(name)
+		buf                            strings.Builder
+		lastCase, nextCase, nextNumber bool // upper case == true
+		curCase                        = value[0] <= 'Z' && value[0] >= 'A'
+	)
+	for i, v := range value[:len(value)-1] {
+		nextCase = value[i+1] <= 'Z' && value[i+1] >= 'A'
+		nextNumber = value[i+1] >= '0' && value[i+1] <= '9'
+		if curCase {
+			if lastCase && (nextCase || nextNumber) {
+				buf.WriteRune(v + 32)
+			} else {
+				if i > 0 && value[i-1] != '_' && value[i+1] != '_' {
+					buf.WriteByte('_')
+				}
+				buf.WriteRune(v + 32)
+			}
+		} else {
+			buf.WriteRune(v)
+		}
+		lastCase = curCase
+		curCase = nextCase
+	}
+	if curCase {
+		if !lastCase && len(value) > 1 {
+			buf.WriteByte('_')
+		}
+		buf.WriteByte(value[len(value)-1] + 32)
+	} else {
+		buf.WriteByte(value[len(value)-1])
+	}
+	return buf.String()
+}
+
+type pluralRule struct {
+	find    *regexp.Regexp
+	replace string
+}
+
+// pluralRules port the rules of github.com/jinzhu/inflection used by GORM,
+// most specific first, for the lower-case names produced by gormDBName.
+var pluralRules = func() []pluralRule {
+	var rules []pluralRule
+	for _, word := range []string{"equipment", "information", "rice", "money", "species", "series", "fish", "sheep", "jeans", "police"} {
+		rules = append(rules, pluralRule{regexp.MustCompile("^(" + word + ")$"), "${1}"})
+	}
+	for _, irregular := range [][2]string{{"person", "people"}, {"man", "men"}, {"child", "children"}, {"sex", "sexes"}, {"move", "moves"}, {"mombie", "mombies"}} {
+		rules = append(rules, pluralRule{regexp.MustCompile(irregular[0] + "$"), irregular[1]})
+	}
+	regular := [][2]string{
+		{"([a-z])$", "${1}s"},
+		{"s$", "s"},
+		{"^(ax|test)is$", "${1}es"},
+		{"(octop|vir)us$", "${1}i"},
+		{"(octop|vir)i$", "${1}i"},
+		{"(alias|status)$", "${1}es"},
+		{"(bu)s$", "${1}ses"},
+		{"(buffal|tomat)o$", "${1}oes"},
+		{"([ti])um$", "${1}a"},
+		{"([ti])a$", "${1}a"},
+		{"sis$", "ses"},
+		{"(?:([^f])fe|([lr])f)$", "${1}${2}ves"},
+		{"(hive)$", "${1}s"},
+		{"([^aeiouy]|qu)y$", "${1}ies"},
+		{"(x|ch|ss|sh)$", "${1}es"},
+		{"(matr|vert|ind)(?:ix|ex)$", "${1}ices"},
+		{"^(m|l)ouse$", "${1}ice"},
+		{"^(m|l)ice$", "${1}ice"},
+		{"^(ox)$", "${1}en"},
+		{"^(oxen)$", "${1}"},
+		{"(quiz)$", "${1}zes"},
+	}
+	for i := len(regular) - 1; i >= 0; i-- {
+		rules = append(rules, pluralRule{regexp.MustCompile(regular[i][0]), regular[i][1]})
+	}
+	return rules
+}()
+
+// pluralize returns the plural form of a lower-case English noun.
+func pluralize(word string) string {
+	for _, rule := range pluralRules {
+		if rule.find.MatchString(word) {
+			return rule.find.ReplaceAllString(word, rule.replace)
+		}
+	}
+	return word
+}
@@ -0,0 +1,59 @@
+package generator
+
+import "testing"
+
+// ---- gormDBName -----------------------------------------------------------------
+
+func TestGormDBName(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Email", "email"},
+		{"UserName", "user_name"},
+		{"ID", "id"},
+		{"UserID", "user_id"},
+		{"UUID", "uuid"},
+		{"HTTPServer", "http_server"},
+		{"JSONResponse", "json_response"},
+		{"ProductSKU", "product_sku"},
+		{"Address2", "address2"},
+		{"CreatedAt", "created_at"},
+		{"Already_Snake", "already_snake"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := gormDBName(tt.input); got != tt.want {
+				t.Errorf("gormDBName(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// ---- NamingStrategy.TableName ---------------------------------------------------
+
+func TestNamingStrategyTableName(t *testing.T) {
+	tests := []struct {
+		naming NamingStrategy
+		input  string
+		want   string
+	}{
+		{NamingStrategy{}, "User", "users"},
+		{NamingStrategy{}, "GormTest", "gorm_tests"},
+		{NamingStrategy{}, "Category", "categories"},
+		{NamingStrategy{}, "Address", "addresses"},
+		{NamingStrategy{}, "Person", "people"},
+		{NamingStrategy{}, "Status", "statuses"},
+		{NamingStrategy{}, "Equipment", "equipment"},
+		{NamingStrategy{}, "Box", "boxes"},
+		{NamingStrategy{}, "Wolf", "wolves"},
+		{NamingStrategy{TablePrefix: "app_"}, "OrderItem", "app_order_items"},
+		{NamingStrategy{SingularTable: true}, "Category", "category"},
+		{NamingStrategy{TablePrefix: "t_", SingularTable: true}, "APIKey", "t_api_key"},
+	}
+	for _, tt := range tests {
+		if got := tt.naming.TableName(tt.input); got != tt.want {
+			t.Errorf("%+v.TableName(%q) = %q, want %q", tt.naming, tt.input, got, tt.want)
+		}
+	}
+}
@@ -1,14 +1,17 @@
 package generator
 
 import (
-	"bufio"
 	"fmt"
 	"go/ast"
+	"go/build"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 )
 
@@ -18,22 +21,305 @@ func parseFile(filename string, tag string) ([]StructMeta, string, error) {
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to parse file %s: %w", filename, err)
 	}
+	// Sibling files of the same package may declare structs embedded by this file
+	siblings := parseSiblingFiles(fset, filename, node.Name.Name)
+	resolver := newPkgResolver(filename)
+	structs, err := parseFiles([]*ast.File{node}, siblings, tag, resolver)
+	if err != nil {
+		return nil, "", err
+	}
+	return structs, node.Name.Name + "_", nil
+}
+
+// parsePackage parses every non-test .go file of the package in dir and merges
+// their structs into a single metamodel. Generated files are skipped so that a
+// destination inside the package does not feed back into the next run.
+func parsePackage(dir string, tag string) ([]StructMeta, string, error) {
+	pkg, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load package %s: %w", dir, err)
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range append(pkg.GoFiles, pkg.CgoFiles...) {
+		filename := filepath.Join(dir, name)
+		node, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to parse file %s: %w", filename, err)
+		}
+		if ast.IsGenerated(node) {
+			continue
+		}
+		files = append(files, node)
+	}
+	resolver := newPkgResolver(filepath.Join(dir, "doc.go"))
+	structs, err := parseFiles(files, nil, tag, resolver)
+	if err != nil {
+		return nil, "", err
+	}
+	return structs, pkg.Name + "_", nil
+}
 
-	// Build local struct type map (same-file resolution)
-	localStructTypes := collectStructTypes(node)
+// parseSiblingFiles parses the other files of filename's package on a best
+// effort basis: files that fail to parse or belong to another package are ignored.
+func parseSiblingFiles(fset *token.FileSet, filename, pkgName string) []*ast.File {
+	dir := filepath.Dir(filename)
+	pkg, err := build.ImportDir(dir, 0)
+	if err != nil && pkg == nil {
+		return nil
+	}
+	var files []*ast.File
+	for _, name := range append(pkg.GoFiles, pkg.CgoFiles...) {
+		path := filepath.Join(dir, name)
+		if same, err := sameFile(path, filename); err != nil || same {
+			continue
+		}
+		node, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil || node.Name.Name != pkgName {
+			continue
+		}
+		files = append(files, node)
+	}
+	return files
+}
 
-	// Build import map and module resolver for cross-package resolution
-	imports := collectImports(node)
-	modulePath, moduleRoot, _ := findModuleInfo(filename)
-	resolver := &pkgResolver{
-		imports:    imports,
-		modulePath: modulePath,
-		moduleRoot: moduleRoot,
-		cache:      make(map[string]map[string]*ast.StructType),
+func sameFile(a, b string) (bool, error) {
+	ai, err := os.Stat(a)
+	if err != nil {
+		return false, err
 	}
+	bi, err := os.Stat(b)
+	if err != nil {
+		return false, err
+	}
+	return os.SameFile(ai, bi), nil
+}
+
+// parseFiles extracts struct metadata from files belonging to the same package.
+// Structs declared in any of the files, or in extra files of the same package,
+// can be embedded by the others.
+func parseFiles(files, extra []*ast.File, tag string, resolver *pkgResolver) ([]StructMeta, error) {
+	// Build local struct type map (same-package resolution)
+	allFiles := append(append([]*ast.File(nil), files...), extra...)
+	local := newPkgScope(allFiles)
+	tableNames := collectTableNames(allFiles)
 
 	var structs []StructMeta
-	packageName := node.Name.Name + "_"
+	for _, node := range files {
+		imports := collectImports(node)
+		for _, decl := range node.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec, ok := spec.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+				structType, ok := typeSpec.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				structName := typeSpec.Name.Name
+				docs := []*ast.CommentGroup{typeSpec.Doc}
+				if !genDecl.Lparen.IsValid() {
+					docs = append(docs, genDecl.Doc)
+				}
+				directives, err := parseDirectives(docs...)
+				if err != nil {
+					return nil, fmt.Errorf("struct %s: %w", structName, err)
+				}
+				if directives.Skip {
+					continue
+				}
+				structTag := tag
+				if directives.Tag != "" {
+					structTag = directives.Tag
+				}
+				tags, err := splitTags(structTag)
+				if err != nil {
+					return nil, fmt.Errorf("struct %s: %w", structName, err)
+				}
+				// Structs that never mention an encoding tag are not documents of that
+				// encoding; directives opt them in explicitly.
+				if !slices.Contains(tags, "gorm") && directives.empty() && !slices.ContainsFunc(tags, func(tag string) bool {
+					return usesTag(structType, tag)
+				}) {
+					continue
+				}
+				var fields []FieldMeta
+				for _, tag := range tags {
+					tagFields, err := parseFields(structType, tag, imports, local, resolver)
+					if err != nil {
+						return nil, fmt.Errorf("struct %s: %w", structName, err)
+					}
+					if len(tags) > 1 {
+						fields = mergeTagFields(fields, tagFields, tag)
+					} else {
+						fields = tagFields
+					}
+				}
+				meta := StructMeta{
+					StructName: structName,
+					Name:       structName,
+					Tags:       tags,
+					TableName:  tableNames[structName],
+					Fields:     fields,
+				}
+				if directives.Name != "" {
+					meta.Name = directives.Name
+				}
+				if directives.Table != "" {
+					meta.TableName = directives.Table
+				}
+				meta.Indexes, err = structIndexes(structType, directives.Indexes, fields)
+				if err != nil {
+					return nil, fmt.Errorf("struct %s: %w", structName, err)
+				}
+				if len(meta.Fields) > 0 {
+					structs = append(structs, meta)
+				}
+			}
+		}
+	}
+	return structs, nil
+}
+
+// collectTableNames finds GORM TableName() methods whose body returns a
+// constant string and maps the receiver type name to that table name.
+// Methods computing the name at runtime are ignored.
+func collectTableNames(files []*ast.File) map[string]string {
+	consts := make(map[string]string)
+	for _, node := range files {
+		for _, decl := range node.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				valueSpec, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+				for i, name := range valueSpec.Names {
+					if i >= len(valueSpec.Values) {
+						break
+					}
+					if value, ok := stringLiteral(valueSpec.Values[i]); ok {
+						consts[name.Name] = value
+					}
+				}
+			}
+		}
+	}
+
+	tableNames := make(map[string]string)
+	for _, node := range files {
+		for _, decl := range node.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Name.Name != "TableName" || funcDecl.Recv == nil || len(funcDecl.Recv.List) != 1 {
+				continue
+			}
+			if funcDecl.Type.Params.NumFields() != 0 || funcDecl.Type.Results.NumFields() != 1 || funcDecl.Body == nil || len(funcDecl.Body.List) != 1 {
+				continue
+			}
+			ret, ok := funcDecl.Body.List[0].(*ast.ReturnStmt)
+			if !ok || len(ret.Results) != 1 {
+				continue
+			}
+			recvType := funcDecl.Recv.List[0].Type
+			if star, ok := recvType.(*ast.StarExpr); ok {
+				recvType = star.X
+			}
+			recv, ok := recvType.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			if value, ok := stringLiteral(ret.Results[0]); ok {
+				tableNames[recv.Name] = value
+			} else if ident, ok := ret.Results[0].(*ast.Ident); ok {
+				if value, ok := consts[ident.Name]; ok {
+					tableNames[recv.Name] = value
+				}
+			}
+		}
+	}
+	return tableNames
+}
+
+// stringLiteral returns the value of a string literal expression.
+func stringLiteral(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	return value, err == nil
+}
+
+// structDecl is a struct type together with the imports of the file declaring it.
+type structDecl struct {
+	structType *ast.StructType
+	imports    map[string]string
+}
+
+// namedDecl is a defined non-struct type, such as "type Status int", with the
+// imports of the file declaring it.
+type namedDecl struct {
+	typ     ast.Expr
+	imports map[string]string
+}
+
+// pkgScope holds the type declarations of one package.
+type pkgScope struct {
+	structs map[string]structDecl
+	named   map[string]namedDecl
+	valuers map[string]bool // types with a Scan or Value method
+}
+
+func newPkgScope(files []*ast.File) *pkgScope {
+	scope := &pkgScope{structs: make(map[string]structDecl), named: make(map[string]namedDecl), valuers: make(map[string]bool)}
+	for _, node := range files {
+		imports := collectImports(node)
+		for name, st := range collectStructTypes(node) {
+			scope.structs[name] = structDecl{structType: st, imports: imports}
+		}
+		for name, typ := range collectNamedTypes(node) {
+			scope.named[name] = namedDecl{typ: typ, imports: imports}
+		}
+		for _, name := range collectValuerTypes(node) {
+			scope.valuers[name] = true
+		}
+	}
+	return scope
+}
+
+// collectValuerTypes lists the types of a file given a Scan or Value method,
+// such as the sql.Scanner and driver.Valuer column types GORM stores as a
+// single column.
+func collectValuerTypes(node *ast.File) []string {
+	var names []string
+	for _, decl := range node.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || len(fn.Recv.List) != 1 || (fn.Name.Name != "Scan" && fn.Name.Name != "Value") {
+			continue
+		}
+		recv := fn.Recv.List[0].Type
+		if star, ok := recv.(*ast.StarExpr); ok {
+			recv = star.X
+		}
+		if ident, ok := recv.(*ast.Ident); ok {
+			names = append(names, ident.Name)
+		}
+	}
+	return names
+}
+
+// collectNamedTypes builds a map of type name -> underlying type expression for
+// the non-struct, non-generic types declared in a file.
+func collectNamedTypes(node *ast.File) map[string]ast.Expr {
+	m := make(map[string]ast.Expr)
 	for _, decl := range node.Decls {
 		genDecl, ok := decl.(*ast.GenDecl)
 		if !ok || genDecl.Tok != token.TYPE {
@@ -41,25 +327,16 @@ func parseFile(filename string, tag string) ([]StructMeta, string, error) {
 		}
 		for _, spec := range genDecl.Specs {
 			typeSpec, ok := spec.(*ast.TypeSpec)
-			if !ok {
+			if !ok || typeSpec.TypeParams != nil {
 				continue
 			}
-			structType, ok := typeSpec.Type.(*ast.StructType)
-			if !ok {
+			if _, ok := typeSpec.Type.(*ast.StructType); ok {
 				continue
 			}
-			structName := typeSpec.Name.Name
-			meta := StructMeta{
-				StructName: structName,
-				Fields:     parseFields(structType, tag, localStructTypes, resolver),
-			}
-			if len(meta.Fields) > 0 {
-				structs = append(structs, meta)
-			}
+			m[typeSpec.Name.Name] = typeSpec.Type
 		}
 	}
-
-	return structs, packageName, nil
+	return m
 }
 
 // collectStructTypes builds a map of struct name -> *ast.StructType for all structs in a file.
@@ -94,171 +371,495 @@ func collectImports(node *ast.File) map[string]string {
 		if imp.Name != nil {
 			localName = imp.Name.Name
 		} else {
-			// Use the last segment of the import path as the default package name
-			parts := strings.Split(path, "/")
-			localName = parts[len(parts)-1]
+			localName = defaultPackageName(path)
 		}
 		m[localName] = path
 	}
 	return m
 }
 
-// findModuleInfo walks up from the source file to find go.mod and reads the module path.
-func findModuleInfo(sourceFile string) (modulePath, moduleRoot string, err error) {
-	absFile, err := filepath.Abs(sourceFile)
-	if err != nil {
-		return "", "", err
+// defaultPackageName guesses the package name of an unnamed import from its
+// path, skipping major version suffixes ("/v2", "gopkg.in/yaml.v3").
+func defaultPackageName(path string) string {
+	parts := strings.Split(path, "/")
+	name := parts[len(parts)-1]
+	if len(parts) > 1 && isMajorVersion(name) {
+		name = parts[len(parts)-2]
 	}
-	dir := filepath.Dir(absFile)
-	for {
-		gomod := filepath.Join(dir, "go.mod")
-		if _, statErr := os.Stat(gomod); statErr == nil {
-			modulePath, err = readModulePath(gomod)
-			if err != nil {
-				return "", "", err
-			}
-			return modulePath, dir, nil
-		}
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			break
-		}
-		dir = parent
+	if i := strings.Index(name, ".v"); i > 0 && isMajorVersion(name[i+1:]) {
+		name = name[:i]
 	}
-	return "", "", fmt.Errorf("go.mod not found")
+	return strings.ReplaceAll(name, "-", "_")
 }
 
-// readModulePath reads the module directive from a go.mod file.
-func readModulePath(gomodPath string) (string, error) {
-	f, err := os.Open(gomodPath)
-	if err != nil {
-		return "", err
+func isMajorVersion(s string) bool {
+	if len(s) < 2 || s[0] != 'v' {
+		return false
 	}
-	defer f.Close()
-	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if strings.HasPrefix(line, "module ") {
-			return strings.TrimSpace(strings.TrimPrefix(line, "module ")), nil
+	for _, r := range s[1:] {
+		if r < '0' || r > '9' {
+			return false
 		}
 	}
-	return "", fmt.Errorf("module directive not found in %s", gomodPath)
+	return true
 }
 
-// pkgResolver resolves struct types from external packages.
+// pkgResolver resolves struct types from imported packages.
 type pkgResolver struct {
-	imports    map[string]string                     // local pkg name -> import path
-	modulePath string                                // e.g., "github.com/namnv2496/metamodel"
-	moduleRoot string                                // abs path to dir containing go.mod
-	cache      map[string]map[string]*ast.StructType // import path -> struct name -> struct type
+	modules *moduleGraph         // locates import paths on disk; nil outside a module
+	modErr  error                // why modules could not be loaded
+	cache   map[string]*pkgScope // import path -> parsed package
+}
+
+func newPkgResolver(sourceFile string) *pkgResolver {
+	modules, err := loadModuleGraph(sourceFile)
+	return &pkgResolver{
+		modules: modules,
+		modErr:  err,
+		cache:   make(map[string]*pkgScope),
+	}
 }
 
-// resolveExternalStruct returns the *ast.StructType for pkgAlias.typeName, or nil if not found.
-func (r *pkgResolver) resolveExternalStruct(pkgAlias, typeName string) (map[string]*ast.StructType, *ast.StructType) {
-	importPath, ok := r.imports[pkgAlias]
+// resolveExternalStruct returns the package scope and struct declaration of
+// pkgAlias.typeName as seen from a file with the given imports.
+func (r *pkgResolver) resolveExternalStruct(imports map[string]string, pkgAlias, typeName string) (*pkgScope, structDecl, error) {
+	importPath, ok := imports[pkgAlias]
 	if !ok {
-		return nil, nil
+		return nil, structDecl{}, fmt.Errorf("package %s is not imported", pkgAlias)
+	}
+	scope, err := r.loadPackage(importPath)
+	if err != nil {
+		return nil, structDecl{}, err
 	}
+	decl, ok := scope.structs[typeName]
+	if !ok {
+		return nil, structDecl{}, fmt.Errorf("struct %s not found in package %s", typeName, importPath)
+	}
+	return scope, decl, nil
+}
 
-	// Check cache
+// loadPackage parses all non-test .go files of importPath.
+func (r *pkgResolver) loadPackage(importPath string) (*pkgScope, error) {
 	if cached, ok := r.cache[importPath]; ok {
-		return cached, cached[typeName]
+		return cached, nil
 	}
-
-	// Compute the on-disk directory for this import path
-	if r.moduleRoot == "" || r.modulePath == "" {
-		return nil, nil
+	if r.modules == nil {
+		return nil, fmt.Errorf("cannot resolve import %s: %w", importPath, r.modErr)
 	}
-	if !strings.HasPrefix(importPath, r.modulePath) {
-		// External module — not resolvable without go/packages
-		return nil, nil
+	pkgDir, err := r.modules.dir(importPath)
+	if err != nil {
+		return nil, err
 	}
-	relPath := strings.TrimPrefix(importPath, r.modulePath)
-	relPath = strings.TrimPrefix(relPath, "/")
-	pkgDir := filepath.Join(r.moduleRoot, relPath)
-
-	// Parse all .go files in that directory
-	structTypes := make(map[string]*ast.StructType)
-	entries, err := os.ReadDir(pkgDir)
+	pkg, err := build.ImportDir(pkgDir, 0)
 	if err != nil {
-		return nil, nil
+		return nil, fmt.Errorf("failed to load package %s from %s: %w", importPath, pkgDir, err)
 	}
 	fset := token.NewFileSet()
-	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") {
-			continue
-		}
-		filePath := filepath.Join(pkgDir, entry.Name())
+	var files []*ast.File
+	for _, name := range append(pkg.GoFiles, pkg.CgoFiles...) {
+		filePath := filepath.Join(pkgDir, name)
 		fileNode, err := parser.ParseFile(fset, filePath, nil, 0)
 		if err != nil {
-			continue
-		}
-		for name, st := range collectStructTypes(fileNode) {
-			structTypes[name] = st
+			return nil, fmt.Errorf("failed to parse file %s: %w", filePath, err)
 		}
+		files = append(files, fileNode)
 	}
-
-	r.cache[importPath] = structTypes
-	return structTypes, structTypes[typeName]
+	scope := newPkgScope(files)
+	r.cache[importPath] = scope
+	return scope, nil
 }
 
-func parseFields(structType *ast.StructType, tag string, structTypes map[string]*ast.StructType, resolver *pkgResolver) []FieldMeta {
+func parseFields(structType *ast.StructType, tag string, imports map[string]string, scope *pkgScope, resolver *pkgResolver) ([]FieldMeta, error) {
+	switch tag {
+	case "gorm":
+		return parseGormFields(structType, imports, scope, resolver, "", "")
+	case "json", "bson":
+		return parseEncoderFields(structType, tag, imports, scope, resolver, "", map[*ast.StructType]bool{structType: true})
+	}
 	var fields []FieldMeta
 	for _, field := range structType.Fields.List {
-		if field.Tag == nil {
+		if field.Tag == nil || len(field.Names) == 0 {
 			continue
 		}
 		tagValue := strings.Trim(field.Tag.Value, "`")
 		structTag := reflect.StructTag(tagValue)
 
-		// Handle embedded structs (anonymous fields with gorm:"embedded")
-		if len(field.Names) == 0 && tag == "gorm" {
-			rawGorm := structTag.Get("gorm")
-			if strings.Contains(rawGorm, "embedded") {
-				fields = append(fields, resolveEmbedded(field.Type, tag, structTypes, resolver)...)
+		tagName := parseTagName(structTag, tag)
+		if tagName == "" || tagName == "-" {
+			continue
+		}
+		arg, argImports := typeArg(field.Type, imports, scope)
+		for _, ident := range field.Names {
+			fields = append(fields, FieldMeta{
+				FieldName:   ident.Name,
+				TagName:     tagName,
+				Type:        typeString(field.Type),
+				Kind:        fieldKind(field.Type, imports, scope, resolver),
+				TypeArg:     arg,
+				TypeImports: argImports,
+			})
+		}
+	}
+	return fields, nil
+}
+
+// multiTagFields are the struct tags a metamodel can expose side by side,
+// mapped to the Field member holding the name under that tag.
+var multiTagFields = map[string]string{"json": "JSON", "bson": "BSON", "gorm": "Column"}
+
+// splitTags splits a comma separated tag list such as "json,bson,gorm".
+func splitTags(tag string) ([]string, error) {
+	var tags []string
+	for _, t := range strings.Split(tag, ",") {
+		t = strings.TrimSpace(t)
+		if t == "" || slices.Contains(tags, t) {
+			continue
+		}
+		tags = append(tags, t)
+	}
+	if len(tags) == 0 {
+		return nil, fmt.Errorf("no tag given")
+	}
+	if len(tags) > 1 {
+		for _, t := range tags {
+			if _, ok := multiTagFields[t]; !ok {
+				return nil, fmt.Errorf("tag %s cannot be combined with other tags, only json, bson and gorm can", t)
 			}
+		}
+	}
+	return tags, nil
+}
+
+// mergeTagFields merges the fields read for another tag into fields, matching
+// them by Go field name and recording every name in FieldMeta.Names. Fields
+// missing from some tags are kept; the first tag naming a field provides its
+// TagName.
+func mergeTagFields(fields, tagFields []FieldMeta, tag string) []FieldMeta {
+	for _, tf := range tagFields {
+		i := slices.IndexFunc(fields, func(f FieldMeta) bool { return f.FieldName == tf.FieldName })
+		if i < 0 {
+			fields = append(fields, FieldMeta{FieldName: tf.FieldName, TagName: tf.TagName, Type: tf.Type, Kind: tf.Kind, TypeArg: tf.TypeArg, TypeImports: tf.TypeImports})
+			i = len(fields) - 1
+		}
+		if fields[i].Names == nil {
+			fields[i].Names = make(map[string]string)
+		}
+		fields[i].Names[tag] = tf.TagName
+		fields[i].Fields = mergeTagFields(fields[i].Fields, tf.Fields, tag)
+	}
+	return fields
+}
+
+// usesTag reports whether any field of the struct carries the tag key.
+func usesTag(structType *ast.StructType, tag string) bool {
+	for _, field := range structType.Fields.List {
+		if field.Tag == nil {
 			continue
 		}
+		if _, ok := reflect.StructTag(strings.Trim(field.Tag.Value, "`")).Lookup(tag); ok {
+			return true
+		}
+	}
+	return false
+}
 
-		tagName := parseTagName(structTag, tag)
-		if tagName == "" || tagName == "-" || tagName == "->" {
+// parseEncoderFields maps struct fields to document keys following the
+// encoding/json and Mongo bson encoders: untagged exported fields keep their Go
+// name (json) or its lower-case form (bson), bson:",inline" structs and, for
+// json, untagged anonymous structs are flattened, and fields of struct type
+// (or pointers and slices of structs) get nested fields whose keys are dotted
+// paths such as "address.city". visiting guards against recursive types.
+func parseEncoderFields(structType *ast.StructType, tag string, imports map[string]string, scope *pkgScope, resolver *pkgResolver, path string, visiting map[*ast.StructType]bool) ([]FieldMeta, error) {
+	var fields []FieldMeta
+	for _, field := range structType.Fields.List {
+		var structTag reflect.StructTag
+		if field.Tag != nil {
+			structTag = reflect.StructTag(strings.Trim(field.Tag.Value, "`"))
+		}
+		rawTag := structTag.Get(tag)
+		if rawTag == "-" {
 			continue
 		}
+		key, opts, _ := strings.Cut(rawTag, ",")
+		inline := tag == "bson" && slices.Contains(strings.Split(opts, ","), "inline")
+		anonymous := len(field.Names) == 0
+
+		if inline || (anonymous && tag == "json" && key == "" && embedsStruct(field.Type, imports, scope, resolver)) {
+			extScope, decl, err := lookupStruct(field.Type, imports, scope, resolver)
+			if err != nil {
+				return nil, err
+			}
+			if visiting[decl.structType] {
+				continue
+			}
+			visiting[decl.structType] = true
+			inlined, err := parseEncoderFields(decl.structType, tag, decl.imports, extScope, resolver, path, visiting)
+			delete(visiting, decl.structType)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, inlined...)
+			continue
+		}
+
+		var names []string
 		for _, ident := range field.Names {
+			names = append(names, ident.Name)
+		}
+		if anonymous {
+			names = append(names, embeddedTypeName(field.Type))
+		}
+		for _, name := range names {
+			if !ast.IsExported(name) {
+				continue
+			}
+			docKey := key
+			if docKey == "" {
+				docKey = name
+				if tag == "bson" {
+					docKey = strings.ToLower(name)
+				}
+			}
+			meta := FieldMeta{
+				FieldName: name,
+				TagName:   path + docKey,
+				Type:      typeString(field.Type),
+				Kind:      fieldKind(field.Type, imports, scope, resolver),
+			}
+			meta.TypeArg, meta.TypeImports = typeArg(field.Type, imports, scope)
+			if extScope, decl, ok := lookupNestedStruct(field.Type, imports, scope, resolver); ok && !visiting[decl.structType] {
+				visiting[decl.structType] = true
+				nested, err := parseEncoderFields(decl.structType, tag, decl.imports, extScope, resolver, meta.TagName+".", visiting)
+				delete(visiting, decl.structType)
+				if err != nil {
+					return nil, err
+				}
+				meta.Fields = nested
+			}
+			fields = append(fields, meta)
+		}
+	}
+	return fields, nil
+}
+
+// lookupNestedStruct resolves the struct stored in a sub-document: a struct, a
+// pointer to a struct or a slice of them. Types that cannot be resolved are
+// treated as scalar values.
+func lookupNestedStruct(fieldType ast.Expr, imports map[string]string, scope *pkgScope, resolver *pkgResolver) (*pkgScope, structDecl, bool) {
+	if array, ok := fieldType.(*ast.ArrayType); ok {
+		fieldType = array.Elt
+	}
+	if star, ok := fieldType.(*ast.StarExpr); ok {
+		fieldType = star.X
+	}
+	switch fieldType.(type) {
+	case *ast.Ident, *ast.SelectorExpr:
+	default:
+		return nil, structDecl{}, false
+	}
+	extScope, decl, err := lookupStruct(fieldType, imports, scope, resolver)
+	if err != nil {
+		return nil, structDecl{}, false
+	}
+	return extScope, decl, true
+}
+
+// embeddedTypeName is the implicit field name of an embedded type.
+func embeddedTypeName(fieldType ast.Expr) string {
+	switch t := fieldType.(type) {
+	case *ast.StarExpr:
+		return embeddedTypeName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// parseGormFields maps struct fields to columns the way GORM's schema parser
+// does: exported fields without a column: option are named by the naming
+// strategy, anonymous and gorm:"embedded" structs are flattened with their
+// embeddedPrefix, and ignored fields (gorm:"-") and associations are left out.
+// Fields of a named embedded struct are prefixed with the embedding field name.
+func parseGormFields(structType *ast.StructType, imports map[string]string, scope *pkgScope, resolver *pkgResolver, namePrefix, columnPrefix string) ([]FieldMeta, error) {
+	var fields []FieldMeta
+	for _, field := range structType.Fields.List {
+		var structTag reflect.StructTag
+		if field.Tag != nil {
+			structTag = reflect.StructTag(strings.Trim(field.Tag.Value, "`"))
+		}
+		settings := parseGormSettings(structTag.Get("gorm"))
+		if ignore, ok := settings["-"]; ok && (ignore == "-" || strings.EqualFold(ignore, "all")) {
+			continue
+		}
+
+		if _, embedded := settings["EMBEDDED"]; (embedded || len(field.Names) == 0) && embedsStruct(field.Type, imports, scope, resolver) {
+			extScope, decl, err := lookupStruct(field.Type, imports, scope, resolver)
+			if err != nil {
+				return nil, err
+			}
+			prefixes := []string{namePrefix}
+			if len(field.Names) > 0 {
+				prefixes = prefixes[:0]
+				for _, ident := range field.Names {
+					prefixes = append(prefixes, namePrefix+ident.Name)
+				}
+			}
+			for _, prefix := range prefixes {
+				embeddedFields, err := parseGormFields(decl.structType, decl.imports, extScope, resolver, prefix, columnPrefix+settings["EMBEDDEDPREFIX"])
+				if err != nil {
+					return nil, err
+				}
+				fields = append(fields, embeddedFields...)
+			}
+			continue
+		}
+
+		// Associations are not columns, even when they name a join table or
+		// a column of their own.
+		if isGormAssociation(field.Type, settings, scope) {
+			continue
+		}
+		column := parseGormTagName(settings)
+		arg, argImports := typeArg(field.Type, imports, scope)
+		names := field.Names
+		if len(names) == 0 {
+			// an embedded non-struct type is a column named after the type
+			names = []*ast.Ident{ast.NewIdent(embeddedTypeName(field.Type))}
+		}
+		for _, ident := range names {
+			if !ident.IsExported() {
+				continue
+			}
+			name := column
+			if name == "" {
+				name = gormDBName(ident.Name)
+			}
 			fields = append(fields, FieldMeta{
-				FieldName: ident.Name,
-				TagName:   tagName,
+				FieldName:   namePrefix + ident.Name,
+				TagName:     columnPrefix + name,
+				Type:        typeString(field.Type),
+				Kind:        fieldKind(field.Type, imports, scope, resolver),
+				TypeArg:     arg,
+				TypeImports: argImports,
 			})
 		}
 	}
-	return fields
+	return fields, nil
 }
 
-func resolveEmbedded(fieldType ast.Expr, tag string, localStructTypes map[string]*ast.StructType, resolver *pkgResolver) []FieldMeta {
+// isGormAssociation reports whether GORM treats a field as a relationship
+// instead of a column: a field carrying relationship settings, or, without an
+// explicit column, a struct of the same package that is not a Scanner or
+// Valuer, or a slice of structs.
+func isGormAssociation(fieldType ast.Expr, settings map[string]string, scope *pkgScope) bool {
+	for _, key := range []string{"MANY2MANY", "ONE2MANY", "FOREIGNKEY", "REFERENCES", "POLYMORPHIC", "JOINFOREIGNKEY", "JOINREFERENCES"} {
+		if _, ok := settings[key]; ok {
+			return true
+		}
+	}
+	for _, key := range []string{"COLUMN", "SERIALIZER", "TYPE"} {
+		if _, ok := settings[key]; ok {
+			return false
+		}
+	}
+	if star, ok := fieldType.(*ast.StarExpr); ok {
+		fieldType = star.X
+	}
+	switch t := fieldType.(type) {
+	case *ast.Ident:
+		_, ok := scope.structs[t.Name]
+		return ok && !scope.valuers[t.Name]
+	case *ast.ArrayType:
+		elem := t.Elt
+		if star, ok := elem.(*ast.StarExpr); ok {
+			elem = star.X
+		}
+		switch e := elem.(type) {
+		case *ast.Ident:
+			_, ok := scope.structs[e.Name]
+			return ok
+		case *ast.SelectorExpr:
+			return true
+		}
+	}
+	return false
+}
+
+// embedsStruct reports whether the type of an embedded field is a struct,
+// following the named types it is declared as. encoding/json, bson and GORM
+// treat an embedded non-struct type, such as type Status string, as a regular
+// field named after the type. Types that cannot be resolved are taken for
+// structs, so that lookupStruct reports them.
+func embedsStruct(fieldType ast.Expr, imports map[string]string, scope *pkgScope, resolver *pkgResolver) bool {
+	for depth := 0; depth <= maxKindDepth; depth++ {
+		switch t := fieldType.(type) {
+		case *ast.StarExpr:
+			fieldType = t.X
+		case *ast.Ident:
+			if _, ok := scope.structs[t.Name]; ok {
+				return true
+			}
+			decl, ok := scope.named[t.Name]
+			if !ok {
+				return types.Universe.Lookup(t.Name) == nil
+			}
+			fieldType, imports = decl.typ, decl.imports
+		case *ast.SelectorExpr:
+			pkg, ok := t.X.(*ast.Ident)
+			if !ok || resolver == nil {
+				return true
+			}
+			importPath, ok := imports[pkg.Name]
+			if !ok {
+				return true
+			}
+			extScope, err := resolver.loadPackage(importPath)
+			if err != nil {
+				return true
+			}
+			if _, ok := extScope.structs[t.Sel.Name]; ok {
+				return true
+			}
+			decl, ok := extScope.named[t.Sel.Name]
+			if !ok {
+				return true
+			}
+			scope, fieldType, imports = extScope, decl.typ, decl.imports
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// lookupStruct locates the declaration of an embedded struct. A struct that
+// cannot be located is an error rather than silently contributing nothing.
+func lookupStruct(fieldType ast.Expr, imports map[string]string, scope *pkgScope, resolver *pkgResolver) (*pkgScope, structDecl, error) {
 	switch t := fieldType.(type) {
 	case *ast.Ident:
 		// Same-package: Entity
-		if st, ok := localStructTypes[t.Name]; ok {
-			return parseFields(st, tag, localStructTypes, resolver)
+		decl, ok := scope.structs[t.Name]
+		if !ok {
+			return nil, structDecl{}, fmt.Errorf("embedded struct %s not found", t.Name)
 		}
+		return scope, decl, nil
 	case *ast.StarExpr:
-		return resolveEmbedded(t.X, tag, localStructTypes, resolver)
+		return lookupStruct(t.X, imports, scope, resolver)
 	case *ast.SelectorExpr:
 		// Cross-package: entity.Entity
 		pkgIdent, ok := t.X.(*ast.Ident)
 		if !ok {
 			break
 		}
-		pkgAlias := pkgIdent.Name
-		typeName := t.Sel.Name
-		if resolver != nil {
-			extStructTypes, st := resolver.resolveExternalStruct(pkgAlias, typeName)
-			if st != nil {
-				return parseFields(st, tag, extStructTypes, resolver)
-			}
+		extScope, decl, err := resolver.resolveExternalStruct(imports, pkgIdent.Name, t.Sel.Name)
+		if err != nil {
+			return nil, structDecl{}, fmt.Errorf("embedded struct %s.%s: %w", pkgIdent.Name, t.Sel.Name, err)
 		}
+		return extScope, decl, nil
 	}
-	return nil
+	return nil, structDecl{}, fmt.Errorf("unsupported embedded type %T", fieldType)
 }
 
 func parseTagName(structTag reflect.StructTag, tagKey string) string {
@@ -268,7 +869,7 @@ func parseTagName(structTag reflect.StructTag, tagKey string) string {
 	}
 	tag := strings.TrimSpace(rawTag)
 	if tagKey == "gorm" {
-		return parseGormTagName(structTag, tag)
+		return parseGormTagName(parseGormSettings(tag))
 	}
 	// Split by comma to handle options like omitempty
 	parts := strings.Split(rawTag, ",")
@@ -278,27 +879,37 @@ func parseTagName(structTag reflect.StructTag, tagKey string) string {
 	return tag
 }
 
-func parseGormTagName(structTag reflect.StructTag, tag string) string {
-	for _, part := range strings.Split(tag, ";") {
-		part = strings.TrimSpace(part)
-		if strings.HasPrefix(part, "column:") {
-			return strings.TrimPrefix(part, "column:")
+// parseGormTagName returns the explicit name of a gorm field: its column, or
+// the join table of a many2many/one2many relation. It is empty when the name
+// is left to the naming strategy.
+func parseGormTagName(settings map[string]string) string {
+	for _, key := range []string{"COLUMN", "MANY2MANY", "ONE2MANY"} {
+		if name := strings.TrimSpace(settings[key]); name != "" {
+			return name
 		}
-		if strings.HasPrefix(part, "many2many:") {
-			return strings.TrimPrefix(part, "many2many:")
-		}
-		if strings.HasPrefix(part, "one2many:") {
-			return strings.TrimPrefix(part, "one2many:")
-		}
-		// retry with json for id primary key
-		rawTag := structTag.Get("json")
-		if rawTag == "" {
-			return ""
+	}
+	return ""
+}
+
+// parseGormSettings splits a gorm tag into upper-cased keys and their values,
+// like GORM's schema.ParseTagSetting. Options without a value map to their key.
+func parseGormSettings(tag string) map[string]string {
+	settings := make(map[string]string)
+	names := strings.Split(tag, ";")
+	for i := 0; i < len(names); i++ {
+		part := names[i]
+		// "\;" escapes the separator inside a value
+		for strings.HasSuffix(part, "\\") && i+1 < len(names) {
+			i++
+			part = part[:len(part)-1] + ";" + names[i]
 		}
-		parts := strings.Split(rawTag, ",")
-		if len(parts) > 0 {
-			return strings.TrimSpace(parts[0])
+		key, value, hasValue := strings.Cut(part, ":")
+		key = strings.TrimSpace(strings.ToUpper(key))
+		if hasValue {
+			settings[key] = strings.ReplaceAll(value, `\"`, `"`)
+		} else if key != "" {
+			settings[key] = key
 		}
 	}
-	return ""
+	return settings
 }
@@ -41,17 +41,16 @@ func TestParseGormTagName(t *testing.T) {
 			want:    "user_name",
 		},
 		{
-			// The json fallback fires on the first non-matching part ("not null"),
-			// so "column:price" is never reached when there is no json tag.
-			name:    "column not first part — returns empty without json fallback",
+			name:    "column not first part",
 			rawGorm: "not null;column:price",
-			want:    "",
+			want:    "price",
 		},
 		{
-			name:    "primaryKey falls back to json tag",
+			// The column is left to the naming strategy instead of the json tag
+			name:    "primaryKey without column yields empty",
 			rawGorm: "primaryKey",
 			rawJSON: "id,omitempty",
-			want:    "id",
+			want:    "",
 		},
 		{
 			name:    "no column and no json yields empty",
@@ -115,8 +114,8 @@ type Order struct {
 	}
 	// "-" tagged field must be excluded
 	want := []FieldMeta{
-		{FieldName: "ID", TagName: "order_id"},
-		{FieldName: "Status", TagName: "status"},
+		{FieldName: "ID", TagName: "order_id", Type: "int", Kind: KindNumber, TypeArg: "int"},
+		{FieldName: "Status", TagName: "status", Type: "string", Kind: KindString, TypeArg: "string"},
 	}
 	if !reflect.DeepEqual(s.Fields, want) {
 		t.Errorf("Fields = %+v, want %+v", s.Fields, want)
@@ -143,15 +142,224 @@ type Item struct {
 		t.Fatalf("got %d structs, want 1", len(structs))
 	}
 	want := []FieldMeta{
-		{FieldName: "ID", TagName: "id"},
-		{FieldName: "Name", TagName: "item_name"},
-		{FieldName: "Price", TagName: "price"},
+		{FieldName: "ID", TagName: "id", Type: "uint", Kind: KindNumber, TypeArg: "uint"},
+		{FieldName: "Name", TagName: "item_name", Type: "string", Kind: KindString, TypeArg: "string"},
+		{FieldName: "Price", TagName: "price", Type: "float64", Kind: KindNumber, TypeArg: "float64"},
 	}
 	if !reflect.DeepEqual(structs[0].Fields, want) {
 		t.Errorf("Fields = %+v, want %+v", structs[0].Fields, want)
 	}
 }
 
+func TestParseFile_GORMNamingStrategy(t *testing.T) {
+	dir := t.TempDir()
+	src := dir + "/models.go"
+	mustWriteFile(t, src, `package models
+
+import "time"
+
+type Base struct {
+	ID        uint
+	CreatedAt time.Time
+}
+
+type Money struct {
+	Amount   int64
+	Currency string `+"`gorm:\"column:ccy\"`"+`
+}
+
+type Tag struct {
+	Name string
+}
+
+type Status string
+
+type Price struct {
+	Amount int64
+}
+
+func (p *Price) Scan(value any) error { return nil }
+
+type Product struct {
+	Base
+	Status
+	UserID     uint
+	Email      string
+	Price      int       `+"`gorm:\"not null\"`"+`
+	SKU        string    `+"`gorm:\"size:64;column:stock_code\"`"+`
+	Cost       Money     `+"`gorm:\"embedded;embeddedPrefix:cost_\"`"+`
+	Tags       []Tag
+	Owner      Tag
+	OwnerID    uint
+	Secret     string    `+"`gorm:\"-\"`"+`
+	ReadOnly   string    `+"`gorm:\"->\"`"+`
+	Migrated   string    `+"`gorm:\"-:migration\"`"+`
+	Attributes Tag       `+"`gorm:\"serializer:json\"`"+`
+	Listed     Price
+	Roles      []Tag     `+"`gorm:\"many2many:product_roles\"`"+`
+	Parts      []*Tag    `+"`gorm:\"many2many:product_parts;column:parts\"`"+`
+	Maker      Tag       `+"`gorm:\"column:maker;foreignKey:OwnerID\"`"+`
+	internal   string
+}
+`)
+
+	structs, _, err := parseFile(src, "gorm")
+	if err != nil {
+		t.Fatalf("parseFile() error = %v", err)
+	}
+	var product StructMeta
+	for _, s := range structs {
+		if s.StructName == "Product" {
+			product = s
+		}
+	}
+	want := []FieldMeta{
+		{FieldName: "ID", TagName: "id"},
+		{FieldName: "CreatedAt", TagName: "created_at"},
+		{FieldName: "Status", TagName: "status"},
+		{FieldName: "UserID", TagName: "user_id"},
+		{FieldName: "Email", TagName: "email"},
+		{FieldName: "Price", TagName: "price"},
+		{FieldName: "SKU", TagName: "stock_code"},
+		{FieldName: "CostAmount", TagName: "cost_amount"},
+		{FieldName: "CostCurrency", TagName: "cost_ccy"},
+		{FieldName: "OwnerID", TagName: "owner_id"},
+		{FieldName: "ReadOnly", TagName: "read_only"},
+		{FieldName: "Migrated", TagName: "migrated"},
+		{FieldName: "Attributes", TagName: "attributes"},
+		{FieldName: "Listed", TagName: "listed"},
+	}
+	if got := namesOnly(product.Fields); !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseFile_BSONEncoderRules(t *testing.T) {
+	dir := t.TempDir()
+	src := dir + "/models.go"
+	mustWriteFile(t, src, `package models
+
+type Audit struct {
+	CreatedBy string `+"`bson:\"created_by\"`"+`
+}
+
+type Geo struct {
+	Lat float64
+	Lng float64
+}
+
+type Address struct {
+	City string `+"`bson:\"city\"`"+`
+	Geo  *Geo
+}
+
+type Item struct {
+	SKU string `+"`bson:\"sku\"`"+`
+}
+
+type Node struct {
+	Name     string `+"`bson:\"name\"`"+`
+	Children []Node `+"`bson:\"children\"`"+`
+}
+
+type User struct {
+	ID       string  `+"`bson:\"_id,omitempty\"`"+`
+	UserName string
+	Audit    `+"`bson:\",inline\"`"+`
+	Address  Address `+"`bson:\"address\"`"+`
+	Items    []Item  `+"`bson:\"items\"`"+`
+	Secret   string  `+"`bson:\"-\"`"+`
+	Tree     Node    `+"`bson:\"tree\"`"+`
+	hidden   string
+}
+`)
+
+	structs, _, err := parseFile(src, "bson")
+	if err != nil {
+		t.Fatalf("parseFile() error = %v", err)
+	}
+	var user StructMeta
+	for _, s := range structs {
+		if s.StructName == "User" {
+			user = s
+		}
+	}
+	want := []FieldMeta{
+		{FieldName: "ID", TagName: "_id"},
+		{FieldName: "UserName", TagName: "username"},
+		{FieldName: "CreatedBy", TagName: "created_by"},
+		{FieldName: "Address", TagName: "address", Fields: []FieldMeta{
+			{FieldName: "City", TagName: "address.city"},
+			{FieldName: "Geo", TagName: "address.geo", Fields: []FieldMeta{
+				{FieldName: "Lat", TagName: "address.geo.lat"},
+				{FieldName: "Lng", TagName: "address.geo.lng"},
+			}},
+		}},
+		{FieldName: "Items", TagName: "items", Fields: []FieldMeta{
+			{FieldName: "SKU", TagName: "items.sku"},
+		}},
+		// Recursive types stop at the first repetition
+		{FieldName: "Tree", TagName: "tree", Fields: []FieldMeta{
+			{FieldName: "Name", TagName: "tree.name"},
+			{FieldName: "Children", TagName: "tree.children"},
+		}},
+	}
+	if got := namesOnly(user.Fields); !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseFile_JSONEncoderRules(t *testing.T) {
+	dir := t.TempDir()
+	src := dir + "/models.go"
+	mustWriteFile(t, src, `package models
+
+type Base struct {
+	ID int `+"`json:\"id\"`"+`
+}
+
+type Meta struct {
+	Version int
+}
+
+type Status string
+
+type Page struct {
+	Base
+	Status
+	Meta     `+"`json:\"meta\"`"+`
+	Title    string `+"`json:\"title,omitempty\"`"+`
+	Body     string
+	Dash     string `+"`json:\"-,\"`"+`
+	Skipped  string `+"`json:\"-\"`"+`
+}
+`)
+
+	structs, _, err := parseFile(src, "json")
+	if err != nil {
+		t.Fatalf("parseFile() error = %v", err)
+	}
+	var page StructMeta
+	for _, s := range structs {
+		if s.StructName == "Page" {
+			page = s
+		}
+	}
+	want := []FieldMeta{
+		{FieldName: "ID", TagName: "id"},
+		{FieldName: "Status", TagName: "Status"},
+		{FieldName: "Meta", TagName: "meta", Fields: []FieldMeta{
+			{FieldName: "Version", TagName: "meta.Version"},
+		}},
+		{FieldName: "Title", TagName: "title"},
+		{FieldName: "Body", TagName: "Body"},
+		{FieldName: "Dash", TagName: "-"},
+	}
+	if got := namesOnly(page.Fields); !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields = %+v, want %+v", got, want)
+	}
+}
+
 func TestParseFile_MultipleStructs(t *testing.T) {
 	dir := t.TempDir()
 	src := dir + "/models.go"
@@ -218,3 +426,348 @@ func TestParseFile_InvalidGoSyntax(t *testing.T) {
 		t.Fatal("expected error for invalid Go syntax, got nil")
 	}
 }
+
+func TestParseFile_TableNameMethod(t *testing.T) {
+	dir := t.TempDir()
+	src := dir + "/models.go"
+	mustWriteFile(t, src, `package models
+
+const itemTable = "inventory_items"
+
+type Order struct {
+	ID int `+"`json:\"id\"`"+`
+}
+
+func (Order) TableName() string { return "purchase_orders" }
+
+type Item struct {
+	ID int `+"`json:\"id\"`"+`
+}
+
+func (i *Item) TableName() string {
+	return itemTable
+}
+
+type Dynamic struct {
+	ID int `+"`json:\"id\"`"+`
+}
+
+func (d Dynamic) TableName() string {
+	return "dyn_" + "x"
+}
+`)
+
+	structs, _, err := parseFile(src, "json")
+	if err != nil {
+		t.Fatalf("parseFile() error = %v", err)
+	}
+	got := make(map[string]string)
+	for _, s := range structs {
+		got[s.StructName] = s.TableName
+	}
+	want := map[string]string{"Order": "purchase_orders", "Item": "inventory_items", "Dynamic": ""}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("table names = %v, want %v", got, want)
+	}
+}
+
+func TestParseFile_TableNameMethodInSiblingFile(t *testing.T) {
+	dir := t.TempDir()
+	src := dir + "/models.go"
+	mustWriteFile(t, src, `package models
+
+type Order struct {
+	ID int `+"`json:\"id\"`"+`
+}
+`)
+	mustWriteFile(t, dir+"/tables.go", `package models
+
+func (Order) TableName() string { return "purchase_orders" }
+`)
+
+	structs, _, err := parseFile(src, "json")
+	if err != nil {
+		t.Fatalf("parseFile() error = %v", err)
+	}
+	if len(structs) != 1 || structs[0].TableName != "purchase_orders" {
+		t.Errorf("structs = %+v, want Order with table purchase_orders", structs)
+	}
+}
+
+func TestParseFile_Directives(t *testing.T) {
+	dir := t.TempDir()
+	src := dir + "/models.go"
+	mustWriteFile(t, src, `package models
+
+// User is stored in Mongo.
+//
+//metamodel:tag=bson
+//metamodel:name=Account
+//metamodel:table=user_accounts
+type User struct {
+	Email string `+"`json:\"email\" bson:\"mail\"`"+`
+}
+
+//metamodel:skip
+type Internal struct {
+	ID int `+"`json:\"id\"`"+`
+}
+
+type (
+	//metamodel:table=orders_v2
+	Order struct {
+		ID int `+"`json:\"id\"`"+`
+	}
+)
+
+func (Order) TableName() string { return "orders" }
+`)
+
+	structs, _, err := parseFile(src, "json")
+	if err != nil {
+		t.Fatalf("parseFile() error = %v", err)
+	}
+	if len(structs) != 2 {
+		t.Fatalf("got %d structs, want 2 (Internal is skipped)", len(structs))
+	}
+	user := structs[0]
+	if user.StructName != "User" || user.Name != "Account" || user.TableName != "user_accounts" {
+		t.Errorf("User = %+v, want name Account and table user_accounts", user)
+	}
+	if want := []FieldMeta{{FieldName: "Email", TagName: "mail"}}; !reflect.DeepEqual(namesOnly(user.Fields), want) {
+		t.Errorf("User fields = %+v, want %+v (read from bson tag)", user.Fields, want)
+	}
+	// The directive wins over the TableName() method
+	if structs[1].TableName != "orders_v2" {
+		t.Errorf("Order table = %q, want orders_v2", structs[1].TableName)
+	}
+}
+
+func TestParseFile_InvalidDirective(t *testing.T) {
+	dir := t.TempDir()
+	src := dir + "/models.go"
+	mustWriteFile(t, src, `package models
+
+//metamodel:tabel=users
+type User struct {
+	ID int `+"`json:\"id\"`"+`
+}
+`)
+
+	if _, _, err := parseFile(src, "json"); err == nil {
+		t.Fatal("expected error for unknown directive, got nil")
+	}
+}
+
+// ---- parsePackage ---------------------------------------------------------------
+
+func TestParsePackage_MergesFiles(t *testing.T) {
+	dir := t.TempDir()
+	mustWriteFile(t, dir+"/base.go", `package models
+
+type Base struct {
+	ID int `+"`gorm:\"column:id\"`"+`
+}
+`)
+	mustWriteFile(t, dir+"/item.go", `package models
+
+type Item struct {
+	Base `+"`gorm:\"embedded\"`"+`
+	Name string `+"`gorm:\"column:name\"`"+`
+}
+`)
+	mustWriteFile(t, dir+"/item_metamodel.go", `// Code generated by metamodel. DO NOT EDIT.
+
+package models
+
+type Generated struct {
+	ID int `+"`gorm:\"column:id\"`"+`
+}
+`)
+
+	structs, pkg, err := parsePackage(dir, "gorm")
+	if err != nil {
+		t.Fatalf("parsePackage() error = %v", err)
+	}
+	if pkg != "models_" {
+		t.Errorf("pkg = %q, want %q", pkg, "models_")
+	}
+	got := make(map[string][]FieldMeta)
+	for _, s := range structs {
+		got[s.StructName] = s.Fields
+	}
+	if _, ok := got["Generated"]; ok {
+		t.Error("generated files must be skipped")
+	}
+	// Base is embedded from another file of the same package
+	want := []FieldMeta{
+		{FieldName: "ID", TagName: "id"},
+		{FieldName: "Name", TagName: "name"},
+	}
+	if !reflect.DeepEqual(namesOnly(got["Item"]), want) {
+		t.Errorf("Item fields = %+v, want %+v", got["Item"], want)
+	}
+}
+
+func TestParsePackage_NoGoFiles(t *testing.T) {
+	_, _, err := parsePackage(t.TempDir(), "json")
+	if err == nil {
+		t.Fatal("expected error for directory without Go files, got nil")
+	}
+}
+
+func TestParseFile_MultipleTags(t *testing.T) {
+	dir := t.TempDir()
+	src := dir + "/models.go"
+	mustWriteFile(t, src, `package models
+
+type User struct {
+	ID       int    `+"`json:\"id\" bson:\"_id\"`"+`
+	Email    string `+"`json:\"emailAddress\" bson:\"email\" gorm:\"column:email_address\"`"+`
+	Password string `+"`json:\"-\" bson:\"password\"`"+`
+}
+`)
+
+	structs, _, err := parseFile(src, "json,bson,gorm")
+	if err != nil {
+		t.Fatalf("parseFile() error = %v", err)
+	}
+	if len(structs) != 1 {
+		t.Fatalf("expected 1 struct, got %d", len(structs))
+	}
+	want := []FieldMeta{
+		{FieldName: "ID", TagName: "id", Names: map[string]string{"json": "id", "bson": "_id", "gorm": "id"}},
+		{FieldName: "Email", TagName: "emailAddress", Names: map[string]string{"json": "emailAddress", "bson": "email", "gorm": "email_address"}},
+		{FieldName: "Password", TagName: "password", Names: map[string]string{"bson": "password", "gorm": "password"}},
+	}
+	if !reflect.DeepEqual(namesOnly(structs[0].Fields), want) {
+		t.Errorf("Fields = %#v, want %#v", structs[0].Fields, want)
+	}
+	if !reflect.DeepEqual(structs[0].Tags, []string{"json", "bson", "gorm"}) {
+		t.Errorf("Tags = %v", structs[0].Tags)
+	}
+}
+
+func TestParseFile_MultipleTagsRejectsOtherTags(t *testing.T) {
+	dir := t.TempDir()
+	src := dir + "/models.go"
+	mustWriteFile(t, src, "package models\n\ntype User struct {\n\tID int `json:\"id\"`\n}\n")
+
+	if _, _, err := parseFile(src, "json,yaml"); err == nil {
+		t.Fatal("expected an error combining json and yaml")
+	}
+}
+
+func TestParseFile_FieldKinds(t *testing.T) {
+	dir := t.TempDir()
+	src := dir + "/models.go"
+	mustWriteFile(t, dir+"/go.mod", "module example.com/models\n\ngo 1.22\n")
+	mustWriteFile(t, src, `package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Status int
+
+type Code = string
+
+type Address struct {
+	City string
+}
+
+type Account struct {
+	Name      string         `+"`json:\"name\"`"+`
+	Nickname  *string        `+"`json:\"nickname\"`"+`
+	Active    bool           `+"`json:\"active\"`"+`
+	Balance   float64        `+"`json:\"balance\"`"+`
+	Status    Status         `+"`json:\"status\"`"+`
+	Code      Code           `+"`json:\"code\"`"+`
+	CreatedAt time.Time      `+"`json:\"created_at\"`"+`
+	Timeout   time.Duration  `+"`json:\"timeout\"`"+`
+	Note      sql.NullString `+"`json:\"note\"`"+`
+	Tags      []string       `+"`json:\"tags\"`"+`
+	Payload   []byte         `+"`json:\"payload\"`"+`
+	Extra     map[string]any `+"`json:\"extra\"`"+`
+	Home      Address        `+"`json:\"home\"`"+`
+}
+`)
+	structs, _, err := parseFile(src, "json")
+	if err != nil {
+		t.Fatalf("parseFile() error = %v", err)
+	}
+	var account StructMeta
+	for _, s := range structs {
+		if s.StructName == "Account" {
+			account = s
+		}
+	}
+	got := make(map[string]string)
+	types := make(map[string]string)
+	for _, f := range account.Fields {
+		got[f.FieldName] = f.Kind
+		types[f.FieldName] = f.Type
+	}
+	want := map[string]string{
+		"Name":      KindString,
+		"Nickname":  KindString,
+		"Active":    KindBool,
+		"Balance":   KindNumber,
+		"Status":    KindNumber,
+		"Code":      KindString,
+		"CreatedAt": KindTime,
+		"Timeout":   KindNumber, // resolved through the standard library
+		"Note":      KindString,
+		"Tags":      KindSlice,
+		"Payload":   KindField,
+		"Extra":     KindField,
+		"Home":      KindField,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("kinds = %v, want %v", got, want)
+	}
+	if types["Nickname"] != "*string" || types["CreatedAt"] != "time.Time" || types["Extra"] != "map[string]any" {
+		t.Errorf("types = %v", types)
+	}
+
+	args := make(map[string]string)
+	for _, f := range account.Fields {
+		args[f.FieldName] = f.TypeArg
+	}
+	wantArgs := map[string]string{
+		"Name":      "string",
+		"Nickname":  "string",
+		"Active":    "bool",
+		"Balance":   "float64",
+		"Status":    "any", // declared in the source package
+		"Code":      "any",
+		"CreatedAt": "time.Time",
+		"Timeout":   "time.Duration",
+		"Note":      "sql.NullString",
+		"Tags":      "[]string",
+		"Payload":   "[]byte",
+		"Extra":     "map[string]any",
+		"Home":      "any",
+	}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("type arguments = %v, want %v", args, wantArgs)
+	}
+	for _, f := range account.Fields {
+		if f.FieldName == "Note" && !reflect.DeepEqual(f.TypeImports, map[string]string{"sql": "database/sql"}) {
+			t.Errorf("Note imports = %v", f.TypeImports)
+		}
+	}
+}
+
+// namesOnly strips the recorded Go types so tests about naming rules can
+// compare field and tag names alone.
+func namesOnly(fields []FieldMeta) []FieldMeta {
+	var stripped []FieldMeta
+	for _, f := range fields {
+		f.Type, f.Kind, f.TypeArg, f.TypeImports = "", "", "", nil
+		f.Fields = namesOnly(f.Fields)
+		stripped = append(stripped, f)
+	}
+	return stripped
+}
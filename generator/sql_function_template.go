@@ -0,0 +1,259 @@
+package generator
+
+const sqlFunctionTemplate = `// Code generated by metamodel. DO NOT EDIT.
+
+package {{.PackageName}}
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm/clause"
+)
+
+// Expr is a computed SQL value of Go type T, such as an aggregate or a
+// function of fields. Like a field it is a clause.Expression, so it can be
+// selected, grouped by and compared, with gorm as well as with QueryBuilder:
+//
+//	total := GormTest_.Id.Count()
+//	query, args := NewQueryBuilder(GormTest_.TableName).
+//		Select(GormTest_.Type, total.As("total")).
+//		GroupBy(GormTest_.Type).
+//		Having(total.Gt(5)).
+//		OrderByExpr(total.Desc()).
+//		Build()
+//
+//	db.Table(GormTest_.TableName).
+//		Select("?, ?", GormTest_.Type, total.As("total")).
+//		Group(GormTest_.Type.String()).
+//		Having(total.Gt(5)).
+//		Order(total.Desc())
+type Expr[T any] struct {
+	sql  string // SQL with ? for each of vars
+	vars []any
+	err  error // reported to the builder, such as an unknown DATE_TRUNC unit
+}
+
+// Build writes the expression, binding its values to placeholders.
+func (e Expr[T]) Build(builder clause.Builder) {
+	if e.err != nil {
+		builder.AddError(e.err)
+		return
+	}
+	clause.Expr{SQL: e.sql, Vars: e.vars}.Build(builder)
+}
+
+// String returns the expression as SQL for DefaultDialect, with its values
+// written as quoted literals.
+func (e Expr[T]) String() string {
+	w := &sqlWriter{dialect: DefaultDialect, inline: true}
+	e.Build(w)
+	return w.String()
+}
+
+// As names the expression in a SELECT list: "expr AS alias"
+func (e Expr[T]) As(alias string) clause.Expression {
+	return clause.Expr{SQL: "? AS ?", Vars: []any{e, clause.Column{Name: alias}}}
+}
+
+// Equal generates the condition "expr = ?"
+func (e Expr[T]) Equal(val T) clause.Expr {
+	return clause.Expr{SQL: "? = ?", Vars: []any{e, val}}
+}
+
+// NotEqual generates the condition "expr <> ?"
+func (e Expr[T]) NotEqual(val T) clause.Expr {
+	return clause.Expr{SQL: "? <> ?", Vars: []any{e, val}}
+}
+
+// Gt generates the condition "expr > ?"
+func (e Expr[T]) Gt(val T) clause.Expr {
+	return clause.Expr{SQL: "? > ?", Vars: []any{e, val}}
+}
+
+// Gte generates the condition "expr >= ?"
+func (e Expr[T]) Gte(val T) clause.Expr {
+	return clause.Expr{SQL: "? >= ?", Vars: []any{e, val}}
+}
+
+// Lt generates the condition "expr < ?"
+func (e Expr[T]) Lt(val T) clause.Expr {
+	return clause.Expr{SQL: "? < ?", Vars: []any{e, val}}
+}
+
+// Lte generates the condition "expr <= ?"
+func (e Expr[T]) Lte(val T) clause.Expr {
+	return clause.Expr{SQL: "? <= ?", Vars: []any{e, val}}
+}
+
+// Asc orders by the expression, for gorm's Order and QueryBuilder.OrderByExpr.
+func (e Expr[T]) Asc() clause.OrderBy {
+	return clause.OrderBy{Expression: clause.Expr{SQL: "?", Vars: []any{e}}}
+}
+
+// Desc orders by the expression descending.
+func (e Expr[T]) Desc() clause.OrderBy {
+	return clause.OrderBy{Expression: clause.Expr{SQL: "? DESC", Vars: []any{e}}}
+}
+
+// Coalesce replaces NULL by fallback: "COALESCE(expr, ?)"
+// Example: GormTest_.Type.Sum().Coalesce(0)
+func (e Expr[T]) Coalesce(fallback T) Expr[T] {
+	return Expr[T]{sql: "COALESCE(?, ?)", vars: []any{e, fallback}}
+}
+
+// CountAll counts the rows of each group: "COUNT(*)"
+func CountAll() Expr[int64] {
+	return Expr[int64]{sql: "COUNT(*)"}
+}
+
+// Count counts the non-NULL values of the column: "COUNT(column)"
+func (f Field[T]) Count() Expr[int64] {
+	return Expr[int64]{sql: "COUNT(?)", vars: []any{f}}
+}
+
+// CountDistinct counts the distinct non-NULL values: "COUNT(DISTINCT column)"
+func (f Field[T]) CountDistinct() Expr[int64] {
+	return Expr[int64]{sql: "COUNT(DISTINCT ?)", vars: []any{f}}
+}
+
+// Min returns the smallest value of the column: "MIN(column)"
+func (f Field[T]) Min() Expr[T] {
+	return Expr[T]{sql: "MIN(?)", vars: []any{f}}
+}
+
+// Max returns the largest value of the column: "MAX(column)"
+func (f Field[T]) Max() Expr[T] {
+	return Expr[T]{sql: "MAX(?)", vars: []any{f}}
+}
+
+// Coalesce replaces NULL by fallback: "COALESCE(column, ?)"
+func (f Field[T]) Coalesce(fallback T) Expr[T] {
+	return Expr[T]{sql: "COALESCE(?, ?)", vars: []any{f, fallback}}
+}
+
+// Sum adds up the column: "SUM(column)". It is NULL for an empty group; see
+// Expr.Coalesce.
+func (f NumberField[T]) Sum() Expr[T] {
+	return Expr[T]{sql: "SUM(?)", vars: []any{f.Field}}
+}
+
+// Avg averages the column: "AVG(column)"
+func (f NumberField[T]) Avg() Expr[float64] {
+	return Expr[float64]{sql: "AVG(?)", vars: []any{f.Field}}
+}
+
+// Lower converts the column to lower case: "LOWER(column)"
+func (f StringField[T]) Lower() Expr[T] {
+	return Expr[T]{sql: "LOWER(?)", vars: []any{f.Field}}
+}
+
+// Upper converts the column to upper case: "UPPER(column)"
+func (f StringField[T]) Upper() Expr[T] {
+	return Expr[T]{sql: "UPPER(?)", vars: []any{f.Field}}
+}
+
+// dateTruncUnits are the units DateTrunc accepts.
+var dateTruncUnits = []string{
+	"microseconds", "milliseconds", "second", "minute", "hour", "day", "week",
+	"month", "quarter", "year", "decade", "century", "millennium",
+}
+
+// DateTrunc truncates the column to the given unit, one of dateTruncUnits
+// such as "day" or "month": "DATE_TRUNC('day', column)". The unit is written
+// into the SQL, so that the same expression selected and grouped by renders
+// identically; another unit is reported as an error by the builder. The
+// function exists in Postgres and a few other databases, not in MySQL or
+// SQLite.
+func (f TimeField[T]) DateTrunc(unit string) Expr[T] {
+	if !slices.Contains(dateTruncUnits, unit) {
+		return Expr[T]{err: fmt.Errorf("DATE_TRUNC unit %q is not one of %s", unit, strings.Join(dateTruncUnits, ", "))}
+	}
+	return Expr[T]{sql: "DATE_TRUNC('" + unit + "', ?)", vars: []any{f.Field}}
+}
+
+// Window is a window function, computed for every row over the rows of its
+// partition: "ROW_NUMBER() OVER (PARTITION BY ... ORDER BY ...)". Select it
+// with As; to filter on it, select it in a subquery or CTE.
+//
+//	NewQueryBuilder(GormTest_.TableName).Select(
+//		GormTest_.Id,
+//		RowNumber().PartitionBy(GormTest_.Type).OrderBy(GormTest_.CreatedAt.Desc()).As("rank"),
+//	)
+type Window[T any] struct {
+	function  Expr[T]
+	partition []clause.Expression
+	order     []clause.Expression
+}
+
+// RowNumber numbers the rows of each partition from 1: "ROW_NUMBER()"
+func RowNumber() Window[int64] {
+	return Window[int64]{function: Expr[int64]{sql: "ROW_NUMBER()"}}
+}
+
+// Rank ranks the rows of each partition, leaving gaps after ties: "RANK()"
+func Rank() Window[int64] {
+	return Window[int64]{function: Expr[int64]{sql: "RANK()"}}
+}
+
+// DenseRank ranks the rows of each partition without gaps: "DENSE_RANK()"
+func DenseRank() Window[int64] {
+	return Window[int64]{function: Expr[int64]{sql: "DENSE_RANK()"}}
+}
+
+// Over computes the aggregate over a window instead of a group.
+// Example: GormTest_.Type.Sum().Over().PartitionBy(GormTest_.FeatureName)
+func (e Expr[T]) Over() Window[T] {
+	return Window[T]{function: e}
+}
+
+// PartitionBy splits the rows into partitions by the given expressions.
+func (w Window[T]) PartitionBy(exprs ...clause.Expression) Window[T] {
+	w.partition = slices.Concat(w.partition, exprs)
+	return w
+}
+
+// OrderBy orders the rows within each partition. Like QueryBuilder.OrderBy,
+// it qualifies the columns of fields given an owner in joined queries.
+func (w Window[T]) OrderBy(cols ...clause.OrderByColumn) Window[T] {
+	w.order = slices.Clone(w.order)
+	for _, col := range cols {
+		w.order = append(w.order, orderColumn(col))
+	}
+	return w
+}
+
+// Build writes the window function call.
+func (w Window[T]) Build(builder clause.Builder) {
+	w.function.Build(builder)
+	builder.WriteString(" OVER (")
+	if len(w.partition) > 0 {
+		builder.WriteString("PARTITION BY ")
+		for i, expr := range w.partition {
+			if i > 0 {
+				builder.WriteString(", ")
+			}
+			expr.Build(builder)
+		}
+	}
+	if len(w.order) > 0 {
+		if len(w.partition) > 0 {
+			builder.WriteByte(' ')
+		}
+		builder.WriteString("ORDER BY ")
+		for i, expr := range w.order {
+			if i > 0 {
+				builder.WriteString(", ")
+			}
+			expr.Build(builder)
+		}
+	}
+	builder.WriteByte(')')
+}
+
+// As names the window function in a SELECT list: "... OVER (...) AS alias"
+func (w Window[T]) As(alias string) clause.Expression {
+	return clause.Expr{SQL: "? AS ?", Vars: []any{w, clause.Column{Name: alias}}}
+}
+`
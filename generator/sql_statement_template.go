@@ -0,0 +1,385 @@
+package generator
+
+const sqlStatementTemplate = `// Code generated by metamodel. DO NOT EDIT.
+
+package {{.PackageName}}
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"gorm.io/gorm/clause"
+)
+
+// ErrMissingWhereClause is returned by UpdateBuilder and DeleteBuilder for a
+// statement without conditions, which would affect every row, unless Force
+// was called.
+var ErrMissingWhereClause = errors.New("missing WHERE clause")
+
+// InsertBuilder builds parameterized SQL INSERT statements from metamodel
+// fields. Values are bound to placeholders; a value that is a
+// clause.Expression, such as clause.Expr{SQL: "CURRENT_TIMESTAMP"}, is
+// written in place.
+//
+//	query, args, err := NewInsertBuilder(GormTest_.TableName).
+//		Columns(GormTest_.FeatureName, GormTest_.Type).
+//		Values("a", 1).
+//		Values("b", 2).
+//		Build()
+type InsertBuilder struct {
+	dialect    Dialect
+	table      string
+	columns    []FieldRef
+	rows       [][]any
+	onConflict *clause.OnConflict
+}
+
+// NewInsertBuilder creates an InsertBuilder for the given table, rendering for
+// DefaultDialect.
+func NewInsertBuilder(tableName string) *InsertBuilder {
+	return &InsertBuilder{dialect: DefaultDialect, table: tableName}
+}
+
+// WithDialect selects the SQL dialect the statement is rendered for.
+func (ib *InsertBuilder) WithDialect(dialect Dialect) *InsertBuilder {
+	ib.dialect = dialect
+	return ib
+}
+
+// Columns sets the columns the rows provide values for.
+func (ib *InsertBuilder) Columns(fields ...FieldRef) *InsertBuilder {
+	ib.columns = append(ib.columns, fields...)
+	return ib
+}
+
+// Values adds a row, with one value per column in the order of Columns.
+func (ib *InsertBuilder) Values(values ...any) *InsertBuilder {
+	ib.rows = append(ib.rows, values)
+	return ib
+}
+
+// OnConflict turns the statement into an upsert, rendered for the dialect:
+// ON CONFLICT for Postgres and SQLite, ON DUPLICATE KEY UPDATE for MySQL.
+// Example: ib.OnConflict(OnConflict(EmbeddedEntity_.Uuid).DoUpdate(EmbeddedEntity_.Value))
+func (ib *InsertBuilder) OnConflict(onConflict clause.OnConflict) *InsertBuilder {
+	ib.onConflict = &onConflict
+	return ib
+}
+
+// Build constructs the SQL INSERT statement and the arguments bound to its
+// placeholders, in order.
+// Expected format: "INSERT INTO table (a, b) VALUES (?, ?), (?, ?)"
+func (ib *InsertBuilder) Build() (string, []any, error) {
+	if len(ib.columns) == 0 {
+		return "", nil, errors.New("insert into " + ib.table + ": no columns")
+	}
+	if len(ib.rows) == 0 {
+		return "", nil, errors.New("insert into " + ib.table + ": no values")
+	}
+	w := &sqlWriter{dialect: ib.dialect}
+	w.WriteString("INSERT INTO ")
+	w.WriteQuoted(clause.Table{Name: ib.table})
+	w.WriteString(" (")
+	for i, col := range ib.columns {
+		if i > 0 {
+			w.WriteString(", ")
+		}
+		w.WriteQuoted(columnName(col))
+	}
+	w.WriteString(") VALUES ")
+	for i, row := range ib.rows {
+		if len(row) != len(ib.columns) {
+			return "", nil, fmt.Errorf("insert into %s: row %d has %d values for %d columns", ib.table, i+1, len(row), len(ib.columns))
+		}
+		if i > 0 {
+			w.WriteString(", ")
+		}
+		w.WriteByte('(')
+		for j, v := range row {
+			if j > 0 {
+				w.WriteString(", ")
+			}
+			w.AddVar(w, v)
+		}
+		w.WriteByte(')')
+	}
+	if ib.onConflict != nil {
+		if err := ib.writeOnConflict(w, *ib.onConflict); err != nil {
+			return "", nil, fmt.Errorf("insert into %s: %w", ib.table, err)
+		}
+	}
+	return w.String(), w.vars, w.err
+}
+
+// Conflict is the conflict target of an upsert; its methods return the gorm
+// clause.OnConflict, which both db.Clauses and InsertBuilder.OnConflict take.
+//
+//	db.Clauses(OnConflict(EmbeddedEntity_.Uuid).DoUpdate(EmbeddedEntity_.Value)).Create(&rows)
+type Conflict struct {
+	columns []clause.Column
+}
+
+// OnConflict starts an upsert on the columns of a unique index or primary
+// key. MySQL ignores the columns and reacts to any duplicate key.
+func OnConflict(columns ...FieldRef) Conflict {
+	c := Conflict{columns: make([]clause.Column, len(columns))}
+	for i, col := range columns {
+		c.columns[i] = columnName(col)
+	}
+	return c
+}
+
+// DoNothing keeps the existing row.
+func (c Conflict) DoNothing() clause.OnConflict {
+	return clause.OnConflict{Columns: c.columns, DoNothing: true}
+}
+
+// DoUpdate overwrites the given columns of the existing row with the values
+// that were to be inserted.
+func (c Conflict) DoUpdate(fields ...FieldRef) clause.OnConflict {
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.columnRef().name
+	}
+	return clause.OnConflict{Columns: c.columns, DoUpdates: clause.AssignmentColumns(names)}
+}
+
+// DoUpdateAll overwrites every inserted column but the conflict target.
+func (c Conflict) DoUpdateAll() clause.OnConflict {
+	return clause.OnConflict{Columns: c.columns, UpdateAll: true}
+}
+
+// DoUpdateSet applies the given assignments to the existing row, e.g.
+// clause.Assignment{Column: clause.Column{Name: "hits"}, Value: clause.Expr{SQL: "hits + 1"}}.
+func (c Conflict) DoUpdateSet(assignments ...clause.Assignment) clause.OnConflict {
+	return clause.OnConflict{Columns: c.columns, DoUpdates: assignments}
+}
+
+func (ib *InsertBuilder) writeOnConflict(w *sqlWriter, oc clause.OnConflict) error {
+	style := upsertOnConflict
+	if d, ok := ib.dialect.(sqlDialect); ok {
+		style = d.upsert
+	}
+	updates := oc.DoUpdates
+	if oc.UpdateAll {
+		for _, col := range ib.columns {
+			name := col.columnRef().name
+			if !slices.ContainsFunc(oc.Columns, func(c clause.Column) bool { return c.Name == name }) {
+				updates = append(updates, clause.Assignment{Column: clause.Column{Name: name}, Value: clause.Column{Table: "excluded", Name: name}})
+			}
+		}
+	}
+
+	switch style {
+	case upsertUnsupported:
+		return fmt.Errorf("upsert is not supported by %s", ib.dialect.Name())
+	case upsertOnDuplicateKey:
+		w.WriteString(" ON DUPLICATE KEY UPDATE ")
+		if oc.DoNothing || len(updates) == 0 {
+			// assigning a column to itself leaves the row unchanged
+			col := columnName(ib.columns[0])
+			if len(oc.Columns) > 0 {
+				col = oc.Columns[0]
+			}
+			self := clause.Assignment{Column: col, Value: col}
+			updates = []clause.Assignment{self}
+		}
+		for i, a := range updates {
+			if i > 0 {
+				w.WriteString(", ")
+			}
+			w.WriteQuoted(a.Column)
+			w.WriteString(" = ")
+			if col, ok := a.Value.(clause.Column); ok && col.Table == "excluded" {
+				w.WriteString("VALUES(")
+				w.WriteQuoted(clause.Column{Name: col.Name})
+				w.WriteByte(')')
+			} else {
+				w.AddVar(w, a.Value)
+			}
+		}
+	default:
+		w.WriteString(" ON CONFLICT")
+		if oc.OnConstraint != "" {
+			w.WriteString(" ON CONSTRAINT ")
+			w.WriteQuoted(oc.OnConstraint)
+		} else if len(oc.Columns) > 0 {
+			w.WriteString(" (")
+			for i, col := range oc.Columns {
+				if i > 0 {
+					w.WriteString(", ")
+				}
+				w.WriteQuoted(col)
+			}
+			w.WriteByte(')')
+			w.writeConditions(" WHERE ", oc.TargetWhere.Exprs)
+		}
+		if oc.DoNothing || len(updates) == 0 {
+			w.WriteString(" DO NOTHING")
+			return nil
+		}
+		if len(oc.Columns) == 0 && oc.OnConstraint == "" {
+			return errors.New("upsert needs conflict columns to update rows")
+		}
+		w.WriteString(" DO UPDATE SET ")
+		for i, a := range updates {
+			if i > 0 {
+				w.WriteString(", ")
+			}
+			w.WriteQuoted(a.Column)
+			w.WriteString(" = ")
+			w.AddVar(w, a.Value)
+		}
+		w.writeConditions(" WHERE ", oc.Where.Exprs)
+	}
+	return nil
+}
+
+// UpdateBuilder builds parameterized SQL UPDATE statements from metamodel
+// fields. Build refuses a statement without Where conditions unless Force
+// was called.
+//
+//	query, args, err := NewUpdateBuilder(GormTest_.TableName).
+//		Set(GormTest_.FeatureName, "renamed").
+//		Incr(GormTest_.Type, 1).
+//		SetExpr(GormTest_.UpdatedAt, clause.Expr{SQL: "CURRENT_TIMESTAMP"}).
+//		Where(GormTest_.Id.Equal(7)).
+//		Build()
+type UpdateBuilder struct {
+	dialect    Dialect
+	table      string
+	sets       []assignment
+	whereConds []clause.Expression
+	force      bool
+}
+
+// assignment is a "column = value" pair of a SET clause.
+type assignment struct {
+	column FieldRef
+	value  clause.Expression
+}
+
+// NewUpdateBuilder creates an UpdateBuilder for the given table, rendering for
+// DefaultDialect.
+func NewUpdateBuilder(tableName string) *UpdateBuilder {
+	return &UpdateBuilder{dialect: DefaultDialect, table: tableName}
+}
+
+// WithDialect selects the SQL dialect the statement is rendered for.
+func (ub *UpdateBuilder) WithDialect(dialect Dialect) *UpdateBuilder {
+	ub.dialect = dialect
+	return ub
+}
+
+// Set assigns a value to the column: "column = ?"
+func (ub *UpdateBuilder) Set(field FieldRef, value any) *UpdateBuilder {
+	return ub.SetExpr(field, clause.Expr{SQL: "?", Vars: []any{value}})
+}
+
+// Incr adds delta to the column: "column = column + ?". A negative delta
+// decrements it.
+func (ub *UpdateBuilder) Incr(field FieldRef, delta any) *UpdateBuilder {
+	return ub.SetExpr(field, clause.Expr{SQL: "? + ?", Vars: []any{columnName(field), delta}})
+}
+
+// SetExpr assigns an expression to the column, such as another field or
+// clause.Expr{SQL: "UPPER(?)", Vars: []any{GormTest_.FeatureName}}.
+func (ub *UpdateBuilder) SetExpr(field FieldRef, expr clause.Expression) *UpdateBuilder {
+	ub.sets = append(ub.sets, assignment{column: field, value: expr})
+	return ub
+}
+
+// Where adds conditions to the WHERE clause, joined with AND.
+func (ub *UpdateBuilder) Where(conditions ...clause.Expression) *UpdateBuilder {
+	ub.whereConds = append(ub.whereConds, conditions...)
+	return ub
+}
+
+// Force allows Build to update every row when there are no conditions.
+func (ub *UpdateBuilder) Force() *UpdateBuilder {
+	ub.force = true
+	return ub
+}
+
+// Build constructs the SQL UPDATE statement and the arguments bound to its
+// placeholders, in order.
+// Expected format: "UPDATE table SET a = ?, b = b + ? WHERE c = ?"
+func (ub *UpdateBuilder) Build() (string, []any, error) {
+	if len(ub.sets) == 0 {
+		return "", nil, errors.New("update " + ub.table + ": no columns to set")
+	}
+	w := &sqlWriter{dialect: ub.dialect}
+	w.WriteString("UPDATE ")
+	w.WriteQuoted(clause.Table{Name: ub.table})
+	w.WriteString(" SET ")
+	for i, set := range ub.sets {
+		if i > 0 {
+			w.WriteString(", ")
+		}
+		w.WriteQuoted(columnName(set.column))
+		w.WriteString(" = ")
+		set.value.Build(w)
+	}
+	if !w.writeConditions(" WHERE ", ub.whereConds) && !ub.force {
+		return "", nil, fmt.Errorf("update %s: %w", ub.table, ErrMissingWhereClause)
+	}
+	return w.String(), w.vars, w.err
+}
+
+// DeleteBuilder builds parameterized SQL DELETE statements. Build refuses a
+// statement without Where conditions unless Force was called.
+//
+//	query, args, err := NewDeleteBuilder(GormTest_.TableName).
+//		Where(GormTest_.IsActive.IsFalse()).
+//		Build()
+type DeleteBuilder struct {
+	dialect    Dialect
+	table      string
+	whereConds []clause.Expression
+	force      bool
+}
+
+// NewDeleteBuilder creates a DeleteBuilder for the given table, rendering for
+// DefaultDialect.
+func NewDeleteBuilder(tableName string) *DeleteBuilder {
+	return &DeleteBuilder{dialect: DefaultDialect, table: tableName}
+}
+
+// WithDialect selects the SQL dialect the statement is rendered for.
+func (b *DeleteBuilder) WithDialect(dialect Dialect) *DeleteBuilder {
+	b.dialect = dialect
+	return b
+}
+
+// Where adds conditions to the WHERE clause, joined with AND.
+func (b *DeleteBuilder) Where(conditions ...clause.Expression) *DeleteBuilder {
+	b.whereConds = append(b.whereConds, conditions...)
+	return b
+}
+
+// Force allows Build to delete every row when there are no conditions.
+func (b *DeleteBuilder) Force() *DeleteBuilder {
+	b.force = true
+	return b
+}
+
+// Build constructs the SQL DELETE statement and the arguments bound to its
+// placeholders, in order.
+// Expected format: "DELETE FROM table WHERE a = ?"
+func (b *DeleteBuilder) Build() (string, []any, error) {
+	w := &sqlWriter{dialect: b.dialect}
+	w.WriteString("DELETE FROM ")
+	w.WriteQuoted(clause.Table{Name: b.table})
+	if !w.writeConditions(" WHERE ", b.whereConds) && !b.force {
+		return "", nil, fmt.Errorf("delete from %s: %w", b.table, ErrMissingWhereClause)
+	}
+	return w.String(), w.vars, w.err
+}
+
+// columnName is the unqualified column of a field, as written in INSERT
+// column lists and SET clauses.
+func columnName(field FieldRef) clause.Column {
+	return clause.Column{Name: field.columnRef().name}
+}
+`
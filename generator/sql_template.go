@@ -0,0 +1,1074 @@
+package generator
+
+const sqlBuilderTemplate = `// Code generated by metamodel. DO NOT EDIT.
+
+package {{.PackageName}}
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// QueryBuilder builds parameterized SQL SELECT statements from metamodel
+// fields and the gorm expressions they produce. Values never end up in the SQL
+// text; Build returns them as arguments bound to placeholders.
+//
+//	query, args := NewQueryBuilder(GormTest_.TableName).
+//		Select(GormTest_.Id, GormTest_.FeatureName).
+//		Where(GormTest_.FeatureName.Equal("test"), GormTest_.IsActive.IsTrue()).
+//		OrderBy(GormTest_.Id.Desc()).
+//		Build()
+//	db.Raw(query, args...).Scan(&results)
+//
+// The *String methods append raw SQL text as written. They are an unsafe
+// escape hatch: anything interpolated into them is neither quoted nor escaped.
+type QueryBuilder struct {
+	dialect     Dialect
+	ctes        []cte
+	fromTable   string
+	joins       []join
+	selectCols  []clause.Expression
+	whereConds  []clause.Expression
+	groupByCols []clause.Expression
+	havingConds []clause.Expression
+	orderByCols []clause.Expression
+	compounds   []compound
+	limit       int
+	offset      int
+	err         error
+}
+
+// NewQueryBuilder creates a new QueryBuilder for the given table, rendering
+// for DefaultDialect. The table may be followed by an alias, e.g. "gorm_tests g".
+func NewQueryBuilder(tableName string) *QueryBuilder {
+	return &QueryBuilder{
+		dialect:   DefaultDialect,
+		fromTable: tableName,
+		limit:     -1,
+	}
+}
+
+// WithDialect selects the SQL dialect the statement is rendered for.
+// Example: NewQueryBuilder(GormTest_.TableName).WithDialect(Postgres)
+func (qb *QueryBuilder) WithDialect(dialect Dialect) *QueryBuilder {
+	qb.dialect = dialect
+	return qb
+}
+
+// Select adds columns or expressions to the SELECT clause.
+// Example: Select(GormTest_.Id, GormTest_.FeatureName)
+func (qb *QueryBuilder) Select(cols ...clause.Expression) *QueryBuilder {
+	qb.selectCols = append(qb.selectCols, cols...)
+	return qb
+}
+
+// SelectString adds raw SQL to the SELECT clause, unsafe like every *String
+// method of QueryBuilder.
+func (qb *QueryBuilder) SelectString(cols ...string) *QueryBuilder {
+	qb.selectCols = append(qb.selectCols, rawSQLs(cols)...)
+	return qb
+}
+
+// join is a JOIN clause of a QueryBuilder.
+type join struct {
+	kind  string
+	table clause.Table
+	on    []clause.Expression
+}
+
+// InnerJoin adds an INNER JOIN of table on the given conditions, joined with
+// AND. The table may be followed by an alias, e.g. "embedded_entity e".
+// Example: InnerJoin(EmbeddedEntity_.TableName, On(GormTest_.Id, EmbeddedEntity_.ParentId))
+func (qb *QueryBuilder) InnerJoin(table string, on ...clause.Expression) *QueryBuilder {
+	return qb.join("INNER JOIN", table, on)
+}
+
+// LeftJoin adds a LEFT JOIN of table on the given conditions.
+func (qb *QueryBuilder) LeftJoin(table string, on ...clause.Expression) *QueryBuilder {
+	return qb.join("LEFT JOIN", table, on)
+}
+
+// RightJoin adds a RIGHT JOIN of table on the given conditions.
+func (qb *QueryBuilder) RightJoin(table string, on ...clause.Expression) *QueryBuilder {
+	return qb.join("RIGHT JOIN", table, on)
+}
+
+func (qb *QueryBuilder) join(kind, table string, on []clause.Expression) *QueryBuilder {
+	qb.joins = append(qb.joins, join{kind: kind, table: parseTable(table), on: on})
+	return qb
+}
+
+// On returns the join condition "left = right" between two columns.
+// Example: On(GormTest_.Id, EmbeddedEntity_.ParentId) → gorm_tests.id = embedded_entity.parent_id
+func On(left, right FieldRef) clause.Expression {
+	return compareFields(left, "=", right)
+}
+
+// parseTable splits "name", "name alias" or "name AS alias".
+func parseTable(table string) clause.Table {
+	parts := strings.Fields(table)
+	switch {
+	case len(parts) == 2:
+		return clause.Table{Name: parts[0], Alias: parts[1]}
+	case len(parts) == 3 && strings.EqualFold(parts[1], "AS"):
+		return clause.Table{Name: parts[0], Alias: parts[2]}
+	}
+	return clause.Table{Name: strings.TrimSpace(table)}
+}
+
+// Where adds conditions to the WHERE clause, joined with AND.
+// Example: Where(GormTest_.FeatureName.Equal("test"), Or(GormTest_.Type.Gt(1), GormTest_.IsActive.IsTrue()))
+func (qb *QueryBuilder) Where(conditions ...clause.Expression) *QueryBuilder {
+	qb.whereConds = append(qb.whereConds, conditions...)
+	return qb
+}
+
+// WhereString adds raw SQL conditions to the WHERE clause, unsafe like
+// every *String method of QueryBuilder.
+func (qb *QueryBuilder) WhereString(conditions ...string) *QueryBuilder {
+	qb.whereConds = append(qb.whereConds, rawSQLs(conditions)...)
+	return qb
+}
+
+// GroupBy adds columns or expressions to the GROUP BY clause.
+func (qb *QueryBuilder) GroupBy(cols ...clause.Expression) *QueryBuilder {
+	qb.groupByCols = append(qb.groupByCols, cols...)
+	return qb
+}
+
+// GroupByString adds raw SQL to the GROUP BY clause, unsafe like
+// every *String method of QueryBuilder.
+func (qb *QueryBuilder) GroupByString(cols ...string) *QueryBuilder {
+	qb.groupByCols = append(qb.groupByCols, rawSQLs(cols)...)
+	return qb
+}
+
+// Having adds conditions to the HAVING clause, joined with AND.
+func (qb *QueryBuilder) Having(conditions ...clause.Expression) *QueryBuilder {
+	qb.havingConds = append(qb.havingConds, conditions...)
+	return qb
+}
+
+// HavingString adds raw SQL conditions to the HAVING clause, unsafe like
+// every *String method of QueryBuilder.
+func (qb *QueryBuilder) HavingString(conditions ...string) *QueryBuilder {
+	qb.havingConds = append(qb.havingConds, rawSQLs(conditions)...)
+	return qb
+}
+
+// OrderBy adds columns to the ORDER BY clause. Columns of fields given an
+// owner with WithOwner or WithDefaultOwner are qualified in joined queries.
+// Example: OrderBy(GormTest_.CreatedAt.Desc(), GormTest_.Id.Asc())
+func (qb *QueryBuilder) OrderBy(cols ...clause.OrderByColumn) *QueryBuilder {
+	for _, col := range cols {
+		qb.orderByCols = append(qb.orderByCols, orderColumn(col))
+	}
+	return qb
+}
+
+// orderColumn is an ORDER BY column, written with qualifyColumn.
+type orderColumn clause.OrderByColumn
+
+func (c orderColumn) Build(builder clause.Builder) {
+	col := clause.OrderByColumn{Column: qualifyColumn(builder, c.Column), Desc: c.Desc}
+	clause.OrderBy{Columns: []clause.OrderByColumn{col}}.Build(builder)
+}
+
+// qualifyColumn returns the column to write for col, whose table is the owner
+// of the field it was taken from, if any; see columnOwner.
+func qualifyColumn(builder clause.Builder, col clause.Column) clause.Column {
+	if q, ok := builder.(columnQualifier); ok {
+		col.Table = q.columnOwner(col.Table)
+	}
+	return col
+}
+
+// OrderByExpr adds expressions to the ORDER BY clause, such as the Asc and
+// Desc of an Expr.
+// Example: OrderByExpr(GormTest_.Id.Count().Desc())
+func (qb *QueryBuilder) OrderByExpr(exprs ...clause.Expression) *QueryBuilder {
+	qb.orderByCols = append(qb.orderByCols, exprs...)
+	return qb
+}
+
+// OrderByString adds raw SQL to the ORDER BY clause, unsafe like
+// every *String method of QueryBuilder.
+func (qb *QueryBuilder) OrderByString(cols ...string) *QueryBuilder {
+	qb.orderByCols = append(qb.orderByCols, rawSQLs(cols)...)
+	return qb
+}
+
+// Limit caps the number of rows returned.
+func (qb *QueryBuilder) Limit(limit int) *QueryBuilder {
+	qb.limit = limit
+	return qb
+}
+
+// Offset skips the first rows of the result.
+func (qb *QueryBuilder) Offset(offset int) *QueryBuilder {
+	qb.offset = offset
+	return qb
+}
+
+// After pages through the rows in the order of cols with keyset pagination:
+// it orders by cols and, unless cursor is empty, keeps only the rows sorting
+// after the row cursor was taken from. Combine it with Limit for the page size.
+//
+//	query, args := NewQueryBuilder(GormTest_.TableName).
+//		After(cursor, GormTest_.CreatedAt.Desc(), GormTest_.Id.Desc()).
+//		Limit(20).
+//		Build()
+//
+// The cursor must hold one value per column; see CursorCodec.
+func (qb *QueryBuilder) After(cursor Cursor, cols ...clause.OrderByColumn) *QueryBuilder {
+	if len(cursor) > 0 {
+		if len(cursor) != len(cols) {
+			qb.addError(fmt.Errorf("%w: %d values for %d order columns", ErrInvalidCursor, len(cursor), len(cols)))
+			return qb
+		}
+		qb.whereConds = append(qb.whereConds, Keyset(cursor, cols...))
+	}
+	return qb.OrderBy(cols...)
+}
+
+// Err returns the first error met while building the query, such as a cursor
+// that does not match the columns given to After.
+func (qb *QueryBuilder) Err() error {
+	return qb.err
+}
+
+func (qb *QueryBuilder) addError(err error) {
+	if qb.err == nil {
+		qb.err = err
+	}
+}
+
+// Build constructs the SQL SELECT statement and the arguments bound to its
+// placeholders, in order. Errors are reported by Err.
+// Expected format: "SELECT a, b FROM table WHERE a = ? GROUP BY ... HAVING ... ORDER BY ..."
+func (qb *QueryBuilder) Build() (string, []any) {
+	w := &sqlWriter{dialect: qb.dialect}
+	qb.writeTo(w)
+	if w.err != nil {
+		qb.addError(w.err)
+	}
+	return w.String(), w.vars
+}
+
+// writeTo writes the statement into w, which may already hold an enclosing
+// statement; its placeholders then continue the numbering of w and it is
+// rendered for the dialect of w.
+func (qb *QueryBuilder) writeTo(w *sqlWriter) {
+	if qb.err != nil {
+		w.AddError(qb.err)
+	}
+	qualify, tables := w.qualify, w.tables
+	defer func() { w.qualify, w.tables = qualify, tables }()
+	// Columns of joined queries are qualified with their table or its alias
+	w.qualify, w.tables = len(qb.joins) > 0, make(map[string]string)
+
+	// WITH clause
+	if len(qb.ctes) > 0 {
+		w.WriteString("WITH ")
+		if slices.ContainsFunc(qb.ctes, func(c cte) bool { return c.recursive }) {
+			if d, ok := w.dialect.(sqlDialect); !ok || !d.plainRecursive {
+				w.WriteString("RECURSIVE ")
+			}
+		}
+		for i, c := range qb.ctes {
+			if i > 0 {
+				w.WriteString(", ")
+			}
+			w.WriteQuoted(c.name)
+			w.WriteString(" AS (")
+			c.query.writeTo(w)
+			w.WriteByte(')')
+		}
+		w.WriteByte(' ')
+	}
+
+	top, tail := w.dialect.Paginate(qb.limit, qb.offset, len(qb.orderByCols) > 0)
+	wrap := len(qb.compounds) > 0 && top != ""
+	if wrap {
+		// TOP would only limit the first query of the compound
+		w.WriteString("SELECT " + top + "* FROM (")
+		top = ""
+	}
+
+	from := parseTable(qb.fromTable)
+	for _, t := range append([]clause.Table{from}, joinTables(qb.joins)...) {
+		if t.Alias != "" {
+			w.tables[t.Name] = t.Alias
+		} else if _, ok := w.tables[t.Name]; !ok {
+			w.tables[t.Name] = t.Name
+		}
+	}
+
+	// SELECT clause
+	w.WriteString("SELECT ")
+	w.WriteString(top)
+	if len(qb.selectCols) > 0 {
+		w.writeList(qb.selectCols, ", ")
+	} else {
+		w.WriteByte('*')
+	}
+
+	// FROM clause
+	w.WriteString(" FROM ")
+	w.WriteQuoted(from)
+
+	// JOIN clauses
+	for _, j := range qb.joins {
+		w.WriteByte(' ')
+		w.WriteString(j.kind)
+		w.WriteByte(' ')
+		w.WriteQuoted(j.table)
+		w.writeConditions(" ON ", j.on)
+	}
+
+	// WHERE clause
+	w.writeConditions(" WHERE ", qb.whereConds)
+
+	// GROUP BY clause
+	if len(qb.groupByCols) > 0 {
+		w.WriteString(" GROUP BY ")
+		w.writeList(qb.groupByCols, ", ")
+	}
+
+	// HAVING clause
+	w.writeConditions(" HAVING ", qb.havingConds)
+
+	// UNION / INTERSECT clauses
+	for _, c := range qb.compounds {
+		w.WriteByte(' ')
+		w.WriteString(c.operator)
+		w.WriteByte(' ')
+		if c.query.standalone() {
+			c.query.writeTo(w)
+		} else {
+			w.WriteByte('(')
+			c.query.writeTo(w)
+			w.WriteByte(')')
+		}
+	}
+	if wrap {
+		w.WriteString(") AS compound")
+	}
+
+	// ORDER BY clause
+	if len(qb.orderByCols) > 0 {
+		w.WriteString(" ORDER BY ")
+		w.writeList(qb.orderByCols, ", ")
+	}
+
+	// LIMIT / OFFSET clause
+	w.WriteString(tail)
+}
+
+// cte is a named query of the WITH clause.
+type cte struct {
+	name      string
+	query     *QueryBuilder
+	recursive bool
+}
+
+// With defines a common table expression the query can select from or join
+// by name: "WITH name AS (SELECT ...) SELECT ..."
+// Example: NewQueryBuilder("recent").With("recent", NewQueryBuilder(GormTest_.TableName).Where(...))
+func (qb *QueryBuilder) With(name string, query *QueryBuilder) *QueryBuilder {
+	qb.ctes = append(qb.ctes, cte{name: name, query: query})
+	return qb
+}
+
+// WithRecursive defines a common table expression that refers to itself,
+// usually the UNION ALL of a starting query and a query joining name:
+// "WITH RECURSIVE name AS (...)". SQLServer writes no RECURSIVE keyword.
+func (qb *QueryBuilder) WithRecursive(name string, query *QueryBuilder) *QueryBuilder {
+	qb.ctes = append(qb.ctes, cte{name: name, query: query, recursive: true})
+	return qb
+}
+
+// compound is a query combined with the rows of another by a set operator.
+type compound struct {
+	operator string
+	query    *QueryBuilder
+}
+
+// Union adds the rows of other, without duplicates: "... UNION SELECT ..."
+// The ORDER BY, Limit and Offset of qb apply to the combined rows; a query
+// with its own ORDER BY, pagination or set operators is parenthesized, which
+// SQLite does not accept.
+func (qb *QueryBuilder) Union(other *QueryBuilder) *QueryBuilder {
+	qb.compounds = append(qb.compounds, compound{operator: "UNION", query: other})
+	return qb
+}
+
+// UnionAll adds the rows of other, keeping duplicates: "... UNION ALL SELECT ..."
+func (qb *QueryBuilder) UnionAll(other *QueryBuilder) *QueryBuilder {
+	qb.compounds = append(qb.compounds, compound{operator: "UNION ALL", query: other})
+	return qb
+}
+
+// Intersect keeps the rows also returned by other: "... INTERSECT SELECT ..."
+func (qb *QueryBuilder) Intersect(other *QueryBuilder) *QueryBuilder {
+	qb.compounds = append(qb.compounds, compound{operator: "INTERSECT", query: other})
+	return qb
+}
+
+// standalone reports whether the query can be a member of a compound without
+// parentheses.
+func (qb *QueryBuilder) standalone() bool {
+	return len(qb.orderByCols) == 0 && qb.limit < 0 && qb.offset <= 0 && len(qb.compounds) == 0
+}
+
+// Subquery returns the query as a parenthesized expression, to be used as a
+// value or a derived table. Its placeholders continue the numbering of the
+// enclosing statement, which also decides its dialect.
+// Example: Where(clause.Expr{SQL: "? > ?", Vars: []any{GormTest_.Type, Subquery(avgType)}})
+func Subquery(qb *QueryBuilder) clause.Expression {
+	return subquery{query: qb}
+}
+
+type subquery struct {
+	query *QueryBuilder
+}
+
+func (s subquery) Build(builder clause.Builder) {
+	if w, ok := builder.(*sqlWriter); ok {
+		w.WriteByte('(')
+		s.query.writeTo(w)
+		w.WriteByte(')')
+		return
+	}
+	// Other builders, such as a gorm statement, bind the arguments themselves
+	w := &sqlWriter{dialect: questionMarks{s.query.dialect}}
+	s.query.writeTo(w)
+	if w.err != nil {
+		builder.AddError(w.err)
+	}
+	clause.Expr{SQL: "(" + w.String() + ")", Vars: w.vars}.Build(builder)
+}
+
+// questionMarks renders a dialect with ? placeholders, which gorm replaces by
+// its own.
+type questionMarks struct {
+	Dialect
+}
+
+func (questionMarks) Placeholder(int) string {
+	return "?"
+}
+
+// Exists generates the condition "EXISTS (SELECT ...)"
+// Example: Exists(NewQueryBuilder(EmbeddedEntity_.TableName).Where(EmbeddedEntity_.ParentId.EqField(GormTest_.Id)))
+func Exists(qb *QueryBuilder) clause.Expression {
+	return clause.Expr{SQL: "EXISTS ?", Vars: []any{Subquery(qb)}}
+}
+
+// NotExists generates the condition "NOT EXISTS (SELECT ...)"
+func NotExists(qb *QueryBuilder) clause.Expression {
+	return clause.Expr{SQL: "NOT EXISTS ?", Vars: []any{Subquery(qb)}}
+}
+
+// Keyset returns the condition selecting the rows that sort after values in
+// the order of cols: "(a, b) > (?, ?)" when all columns sort the same way and
+// the dialect compares row values, otherwise the equivalent
+// "a > ? OR (a = ? AND b > ?)". Descending columns compare with "<".
+// Columns of fields are qualified like in OrderBy, so that the condition is
+// unambiguous in joined queries.
+// It can also be used with gorm: db.Where(Keyset(cursor, cols...)).Order(...)
+func Keyset(values Cursor, cols ...clause.OrderByColumn) clause.Expression {
+	return keyset{cols: cols, values: values}
+}
+
+type keyset struct {
+	cols   []clause.OrderByColumn
+	values []any
+}
+
+func (k keyset) Build(builder clause.Builder) {
+	n := min(len(k.cols), len(k.values))
+	if n == 0 {
+		return
+	}
+	uniform := true
+	for _, col := range k.cols[1:n] {
+		uniform = uniform && col.Desc == k.cols[0].Desc
+	}
+	if rv, ok := builder.(rowValueComparer); n > 1 && uniform && ok && rv.rowValues() {
+		builder.WriteByte('(')
+		for i, col := range k.cols[:n] {
+			if i > 0 {
+				builder.WriteString(", ")
+			}
+			builder.WriteQuoted(qualifyColumn(builder, col.Column))
+		}
+		builder.WriteString(") " + keysetOperator(k.cols[0]) + " (")
+		for i, v := range k.values[:n] {
+			if i > 0 {
+				builder.WriteString(", ")
+			}
+			builder.AddVar(builder, v)
+		}
+		builder.WriteByte(')')
+		return
+	}
+	if n > 1 {
+		builder.WriteByte('(')
+	}
+	for i := range n {
+		if i > 0 {
+			builder.WriteString(" OR ")
+		}
+		if i > 0 {
+			builder.WriteByte('(')
+			for j := range i {
+				builder.WriteQuoted(qualifyColumn(builder, k.cols[j].Column))
+				builder.WriteString(" = ")
+				builder.AddVar(builder, k.values[j])
+				builder.WriteString(" AND ")
+			}
+		}
+		builder.WriteQuoted(qualifyColumn(builder, k.cols[i].Column))
+		builder.WriteString(" " + keysetOperator(k.cols[i]) + " ")
+		builder.AddVar(builder, k.values[i])
+		if i > 0 {
+			builder.WriteByte(')')
+		}
+	}
+	if n > 1 {
+		builder.WriteByte(')')
+	}
+}
+
+func keysetOperator(col clause.OrderByColumn) string {
+	if col.Desc {
+		return "<"
+	}
+	return ">"
+}
+
+// rowValueComparer is implemented by builders whose dialect compares row
+// values such as "(a, b) > (?, ?)".
+type rowValueComparer interface {
+	rowValues() bool
+}
+
+// ErrInvalidCursor is returned for cursors that were altered, signed with
+// another key or do not match the order columns.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor holds the values of the order columns of the last row of a page, in
+// the order of the columns.
+type Cursor []any
+
+// CursorCodec turns cursors into opaque tokens for clients and back. Tokens
+// are signed with HMAC-SHA256, so a client cannot forge or alter them; they
+// are not encrypted. Values keep their Go type; types other than the
+// predeclared ones and time.Time must be registered with gob.Register.
+//
+//	codec, err := NewCursorCodec(secret)
+//	...
+//	next, err := codec.Encode(last.CreatedAt, last.Id)
+//	...
+//	cursor, err := codec.Decode(next)
+type CursorCodec struct {
+	key []byte
+}
+
+// minCursorKeyLen is the length of the shortest key NewCursorCodec accepts.
+const minCursorKeyLen = 16
+
+// NewCursorCodec returns a CursorCodec signing with key, which should be a
+// random secret of at least 32 bytes. Keys shorter than 16 bytes, including
+// an empty one, are rejected with an error.
+func NewCursorCodec(key []byte) (CursorCodec, error) {
+	if len(key) < minCursorKeyLen {
+		return CursorCodec{}, fmt.Errorf("cursor key of %d bytes is shorter than %d bytes", len(key), minCursorKeyLen)
+	}
+	return CursorCodec{key: slices.Clone(key)}, nil
+}
+
+func init() {
+	gob.Register(time.Time{})
+}
+
+// Encode returns the token for the cursor made of values.
+func (c CursorCodec) Encode(values ...any) (string, error) {
+	var payload bytes.Buffer
+	if err := gob.NewEncoder(&payload).Encode(values); err != nil {
+		return "", fmt.Errorf("encode cursor: %w", err)
+	}
+	token := append(c.sign(payload.Bytes()), payload.Bytes()...)
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// Decode verifies token and returns its cursor. An empty token is the empty
+// cursor of the first page.
+func (c CursorCodec) Decode(token string) (Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(raw) < sha256.Size {
+		return nil, ErrInvalidCursor
+	}
+	mac, payload := raw[:sha256.Size], raw[sha256.Size:]
+	if !hmac.Equal(mac, c.sign(payload)) {
+		return nil, ErrInvalidCursor
+	}
+	var values []any
+	if err := gob.NewDecoder(bytes.NewReader(payload)).Decode(&values); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return values, nil
+}
+
+func (c CursorCodec) sign(payload []byte) []byte {
+	h := hmac.New(sha256.New, c.key)
+	h.Write(payload)
+	return h.Sum(nil)
+}
+
+func joinTables(joins []join) []clause.Table {
+	tables := make([]clause.Table, len(joins))
+	for i, j := range joins {
+		tables[i] = j.table
+	}
+	return tables
+}
+
+// rawSQL is SQL text written verbatim, without placeholders.
+type rawSQL string
+
+func (r rawSQL) Build(builder clause.Builder) {
+	builder.WriteString(string(r))
+}
+
+func rawSQLs(sqls []string) []clause.Expression {
+	exprs := make([]clause.Expression, len(sqls))
+	for i, s := range sqls {
+		exprs[i] = rawSQL(s)
+	}
+	return exprs
+}
+
+// sqlWriter implements clause.Builder, rendering gorm expressions into SQL
+// text with placeholders and collecting their arguments.
+type sqlWriter struct {
+	dialect Dialect
+	sql     []byte
+	vars    []any
+	err     error
+	qualify bool              // qualify field columns with their table
+	tables  map[string]string // table -> name it is queried under, its alias or itself
+	inline  bool              // write values into the SQL as quoted literals instead of binding them
+}
+
+// columnQualifier is implemented by builders that may qualify field columns.
+// qualifier returns the table or alias to write before a column of table, or
+// "" to leave it unqualified; alias returns the name table is queried under;
+// columnOwner is qualifier for a column owned by a table or by an alias.
+type columnQualifier interface {
+	qualifier(table string) string
+	alias(table string) string
+	columnOwner(owner string) string
+}
+
+func (w *sqlWriter) qualifier(table string) string {
+	if !w.qualify || table == "" {
+		return ""
+	}
+	return w.alias(table)
+}
+
+func (w *sqlWriter) alias(table string) string {
+	if alias, ok := w.tables[table]; ok {
+		return alias
+	}
+	return table
+}
+
+// columnOwner keeps owner when it is the alias of a table of the query, and
+// otherwise takes it for a table like the columns of fields without an alias.
+func (w *sqlWriter) columnOwner(owner string) string {
+	if _, ok := w.tables[owner]; !ok {
+		for _, alias := range w.tables {
+			if alias == owner {
+				return owner
+			}
+		}
+	}
+	return w.qualifier(owner)
+}
+
+func (w *sqlWriter) WriteByte(c byte) error {
+	w.sql = append(w.sql, c)
+	return nil
+}
+
+func (w *sqlWriter) WriteString(s string) (int, error) {
+	w.sql = append(w.sql, s...)
+	return len(s), nil
+}
+
+func (w *sqlWriter) String() string {
+	return string(w.sql)
+}
+
+// WriteQuoted writes a table or column reference, quoted for the dialect
+// unless marked Raw.
+func (w *sqlWriter) WriteQuoted(field any) {
+	switch v := field.(type) {
+	case clause.Table:
+		w.writeIdentifier(v.Name, v.Raw)
+		if v.Alias != "" {
+			w.WriteByte(' ')
+			w.writeIdentifier(v.Alias, v.Raw)
+		}
+	case clause.Column:
+		if v.Table != "" {
+			w.writeIdentifier(v.Table, v.Raw)
+			w.WriteByte('.')
+		}
+		w.writeIdentifier(v.Name, v.Raw)
+		if v.Alias != "" {
+			w.WriteString(" AS ")
+			w.writeIdentifier(v.Alias, v.Raw)
+		}
+	case []clause.Column:
+		w.WriteByte('(')
+		for i, c := range v {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteQuoted(c)
+		}
+		w.WriteByte(')')
+	case clause.Expression:
+		v.Build(w)
+	case string:
+		w.writeIdentifier(v, false)
+	default:
+		w.AddError(fmt.Errorf("unsupported column %T", field))
+	}
+}
+
+func (w *sqlWriter) writeIdentifier(name string, raw bool) {
+	if raw {
+		w.WriteString(name)
+		return
+	}
+	w.WriteString(w.dialect.QuoteIdentifier(name))
+}
+
+// AddVar writes placeholders for vars, following gorm's rules: columns and
+// expressions are written in place, []any becomes a parenthesized list and
+// other slices are expanded.
+func (w *sqlWriter) AddVar(writer clause.Writer, vars ...any) {
+	for i, v := range vars {
+		if i > 0 {
+			writer.WriteByte(',')
+		}
+		switch v := v.(type) {
+		case sql.NamedArg:
+			w.bind(writer, v.Value)
+		case clause.Column, clause.Table:
+			w.WriteQuoted(v)
+		case clause.Expression:
+			v.Build(w)
+		case driver.Valuer, []byte:
+			w.bind(writer, v)
+		case []any:
+			if len(v) == 0 {
+				writer.WriteString("(NULL)")
+				continue
+			}
+			writer.WriteByte('(')
+			w.AddVar(writer, v...)
+			writer.WriteByte(')')
+		default:
+			rv := reflect.ValueOf(v)
+			if (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) || rv.Type().Elem().Kind() == reflect.Uint8 {
+				w.bind(writer, v)
+				continue
+			}
+			if rv.Len() == 0 {
+				writer.WriteString("(NULL)")
+				continue
+			}
+			writer.WriteByte('(')
+			for j := 0; j < rv.Len(); j++ {
+				if j > 0 {
+					writer.WriteByte(',')
+				}
+				w.AddVar(writer, rv.Index(j).Interface())
+			}
+			writer.WriteByte(')')
+		}
+	}
+}
+
+// bind records an argument and writes its placeholder, or writes the value
+// itself as a literal when inline.
+func (w *sqlWriter) bind(writer clause.Writer, v any) {
+	if w.inline {
+		writer.WriteString(w.literal(v))
+		return
+	}
+	w.vars = append(w.vars, v)
+	writer.WriteString(w.dialect.Placeholder(len(w.vars)))
+}
+
+// literal returns v as an SQL literal of the dialect: numbers are written as
+// they are, booleans with BoolLiteral and anything else as a quoted string.
+func (w *sqlWriter) literal(v any) string {
+	if valuer, ok := v.(driver.Valuer); ok {
+		value, err := valuer.Value()
+		if err != nil {
+			w.AddError(err)
+			return "NULL"
+		}
+		v = value
+	}
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		return w.dialect.BoolLiteral(v)
+	case []byte:
+		return w.quoteString(string(v))
+	case time.Time:
+		return w.quoteString(v.Format("2006-01-02 15:04:05.999999999"))
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return "NULL"
+		}
+		return w.literal(rv.Elem().Interface())
+	case reflect.Bool:
+		return w.dialect.BoolLiteral(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return fmt.Sprint(v)
+	}
+	return w.quoteString(fmt.Sprint(v))
+}
+
+// quoteString quotes s as a string literal, doubling single quotes, and
+// backslashes too for dialects that treat them as escapes.
+func (w *sqlWriter) quoteString(s string) string {
+	s = strings.ReplaceAll(s, "'", "''")
+	if d, ok := w.dialect.(sqlDialect); ok && d.backslashEscapes {
+		s = strings.ReplaceAll(s, "\\", "\\\\")
+	}
+	return "'" + s + "'"
+}
+
+func (w *sqlWriter) rowValues() bool {
+	d, ok := w.dialect.(sqlDialect)
+	return ok && d.rowValues
+}
+
+func (w *sqlWriter) AddError(err error) error {
+	if w.err == nil {
+		w.err = err
+	}
+	return err
+}
+
+// writeList writes exprs separated by sep.
+func (w *sqlWriter) writeList(exprs []clause.Expression, sep string) {
+	for i, expr := range exprs {
+		if i > 0 {
+			w.WriteString(sep)
+		}
+		expr.Build(w)
+	}
+}
+
+// writeConditions writes keyword, such as " WHERE ", and the conditions
+// joined with AND, parenthesizing OR groups the way gorm's WHERE clause does.
+// It reports whether any condition was written: empty And and Or groups are
+// left out, and without conditions that render SQL the keyword is not
+// written either.
+func (w *sqlWriter) writeConditions(keyword string, conditions []clause.Expression) bool {
+	conditions = pruneConditions(conditions)
+	if len(conditions) == 0 {
+		return false
+	}
+	start := len(w.sql)
+	w.WriteString(keyword)
+	body := len(w.sql)
+	clause.Where{Exprs: conditions}.Build(w)
+	if strings.TrimSpace(string(w.sql[body:])) == "" {
+		w.sql = w.sql[:start]
+		return false
+	}
+	return true
+}
+
+// pruneConditions returns conditions without the And and Or groups holding
+// no condition, which render as nothing.
+func pruneConditions(conditions []clause.Expression) []clause.Expression {
+	var kept []clause.Expression
+	for _, c := range conditions {
+		switch v := c.(type) {
+		case nil:
+			continue
+		case clause.AndConditions:
+			if v.Exprs = pruneConditions(v.Exprs); len(v.Exprs) == 0 {
+				continue
+			}
+			c = v
+		case clause.OrConditions:
+			if v.Exprs = pruneConditions(v.Exprs); len(v.Exprs) == 0 {
+				continue
+			}
+			c = v
+		}
+		kept = append(kept, c)
+	}
+	return kept
+}
+
+// Dialect renders the parts of SQL that differ between databases. Postgres,
+// MySQL, SQLite and SQLServer are provided; GenericSQL keeps identifiers bare.
+type Dialect interface {
+	// Name identifies the dialect, e.g. "postgres".
+	Name() string
+	// QuoteIdentifier quotes a table, column or alias name. Dotted names are
+	// quoted part by part and "*" is left alone.
+	QuoteIdentifier(name string) string
+	// Placeholder returns the placeholder of the n-th argument, counting from 1.
+	Placeholder(n int) string
+	// BoolLiteral returns the literal written for a boolean value.
+	BoolLiteral(v bool) string
+	// Paginate returns the text written right after SELECT (such as "TOP (10) ")
+	// and at the end of the statement (such as " LIMIT 10 OFFSET 20"). A negative
+	// limit means no limit; ordered reports whether the query has an ORDER BY.
+	Paginate(limit, offset int, ordered bool) (afterSelect, tail string)
+}
+
+// DefaultDialect is used by NewQueryBuilder and the *String helpers.
+var DefaultDialect Dialect = GenericSQL
+
+var (
+	// GenericSQL writes bare identifiers, ? placeholders and LIMIT/OFFSET.
+	GenericSQL Dialect = sqlDialect{name: "generic", placeholder: questionMark, trueLiteral: "true", falseLiteral: "false"}
+	// Postgres quotes "identifiers" and numbers placeholders $1, $2, ...
+	Postgres Dialect = sqlDialect{name: "postgres", quoteOpen: '"', quoteClose: '"', placeholder: dollarNumber, trueLiteral: "TRUE", falseLiteral: "FALSE", rowValues: true}
+	// MySQL quotes ` + "`identifiers`" + ` and uses ? placeholders.
+	MySQL Dialect = sqlDialect{name: "mysql", quoteOpen: '` + "`" + `', quoteClose: '` + "`" + `', placeholder: questionMark, trueLiteral: "TRUE", falseLiteral: "FALSE", unlimited: "18446744073709551615", rowValues: true, upsert: upsertOnDuplicateKey, backslashEscapes: true}
+	// SQLite quotes ` + "`identifiers`" + ` and uses ? placeholders.
+	SQLite Dialect = sqlDialect{name: "sqlite", quoteOpen: '` + "`" + `', quoteClose: '` + "`" + `', placeholder: questionMark, trueLiteral: "1", falseLiteral: "0", unlimited: "-1", rowValues: true}
+	// SQLServer quotes [identifiers], uses @p1, @p2, ... placeholders and
+	// paginates with TOP or OFFSET ... FETCH.
+	SQLServer Dialect = sqlDialect{name: "sqlserver", quoteOpen: '[', quoteClose: ']', placeholder: atNumber, trueLiteral: "1", falseLiteral: "0", fetch: true, upsert: upsertUnsupported, plainRecursive: true}
+)
+
+// upsert styles of the dialects.
+const (
+	upsertOnConflict     = "" // ON CONFLICT (...) DO UPDATE SET col = excluded.col
+	upsertOnDuplicateKey = "on duplicate key"
+	upsertUnsupported    = "unsupported"
+)
+
+func questionMark(int) string  { return "?" }
+func dollarNumber(n int) string { return "$" + strconv.Itoa(n) }
+func atNumber(n int) string     { return "@p" + strconv.Itoa(n) }
+
+// sqlDialect is a table-driven Dialect.
+type sqlDialect struct {
+	name                    string
+	quoteOpen, quoteClose   byte // zero leaves identifiers bare
+	placeholder             func(n int) string
+	trueLiteral, falseLiteral string
+	unlimited               string // LIMIT value written when only an offset is set, empty if OFFSET stands alone
+	fetch                   bool   // TOP / OFFSET ... FETCH instead of LIMIT / OFFSET
+	rowValues               bool   // compares row values: (a, b) > (?, ?)
+	upsert                  string // upsert style of InsertBuilder.OnConflict
+	plainRecursive          bool   // WITH without RECURSIVE for recursive queries
+	backslashEscapes        bool   // backslashes escape characters in string literals
+}
+
+func (d sqlDialect) Name() string {
+	return d.name
+}
+
+func (d sqlDialect) QuoteIdentifier(name string) string {
+	name = strings.TrimSpace(name)
+	if d.quoteOpen == 0 || name == "" {
+		return name
+	}
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		if part == "*" {
+			continue
+		}
+		escaped := strings.ReplaceAll(part, string(d.quoteClose), string(d.quoteClose)+string(d.quoteClose))
+		parts[i] = string(d.quoteOpen) + escaped + string(d.quoteClose)
+	}
+	return strings.Join(parts, ".")
+}
+
+func (d sqlDialect) Placeholder(n int) string {
+	return d.placeholder(n)
+}
+
+func (d sqlDialect) BoolLiteral(v bool) string {
+	if v {
+		return d.trueLiteral
+	}
+	return d.falseLiteral
+}
+
+func (d sqlDialect) Paginate(limit, offset int, ordered bool) (string, string) {
+	if d.fetch {
+		if offset <= 0 {
+			if limit < 0 {
+				return "", ""
+			}
+			return "TOP (" + strconv.Itoa(limit) + ") ", ""
+		}
+		var tail strings.Builder
+		if !ordered {
+			// OFFSET ... FETCH is only valid after ORDER BY
+			tail.WriteString(" ORDER BY (SELECT NULL)")
+		}
+		tail.WriteString(" OFFSET " + strconv.Itoa(offset) + " ROWS")
+		if limit >= 0 {
+			tail.WriteString(" FETCH NEXT " + strconv.Itoa(limit) + " ROWS ONLY")
+		}
+		return "", tail.String()
+	}
+	var tail string
+	switch {
+	case limit >= 0:
+		tail = " LIMIT " + strconv.Itoa(limit)
+	case offset > 0 && d.unlimited != "":
+		tail = " LIMIT " + d.unlimited
+	}
+	if offset > 0 {
+		tail += " OFFSET " + strconv.Itoa(offset)
+	}
+	return "", tail
+}
+`
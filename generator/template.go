@@ -4,21 +4,126 @@ const metamodelTemplate = `// Code generated by metamodel. DO NOT EDIT.
 // Author: namnv2496
 
 package {{.PackageName}}
-{{range .Structs}}
-{{- $tableName := tableName .StructName -}}
+{{with .Imports}}
+import (
+{{- range .}}
+	{{with .Name}}{{.}} {{end}}"{{.Path}}"
+{{- end}}
+)
+{{end}}
+{{- range .Structs}}
+{{- $tableName := tableName . -}}
+{{- $struct := . -}}
+{{range nestedTypes .}}
+// {{.TypeName}} contains field name constants for the {{.Path}} document
+type {{.TypeName}} struct {
+	{{if .Slice}}SliceField{{else}}Field{{end}}[any]
+{{- range .Fields}}
+	{{.FieldName}} {{.Type}}
+{{- end}}
+}
+{{- if .Array}}
+
+// At returns the fields of element i of the {{.Path}} array.
+func (d {{.TypeName}}) At(i int) {{.TypeName}} {
+	d.rebase(d.Field, d.SliceField.At(i))
+	return d
+}
+
+// Positional returns the fields of the element of the {{.Path}} array
+// matched by the filter of an update.
+func (d {{.TypeName}}) Positional() {{.TypeName}} {
+	d.rebase(d.Field, d.SliceField.Positional())
+	return d
+}
 
-// {{.StructName}}_ contains field name constants for {{.StructName}}
-var {{.StructName}}_ = struct {
+// AllPositional returns the fields of every element of the {{.Path}} array,
+// for updates.
+func (d {{.TypeName}}) AllPositional() {{.TypeName}} {
+	d.rebase(d.Field, d.SliceField.AllPositional())
+	return d
+}
+{{- end}}
+
+{{- if .Owner}}
+
+// WithOwner returns the fields of the {{.Path}} document qualified by the
+// table or alias val.
+func (d {{.TypeName}}) WithOwner(val string) {{.TypeName}} {
+	d.Field = d.Field.WithOwner(val)
+{{- range .Fields}}
+	d.{{.FieldName}} = d.{{.FieldName}}.WithOwner(val)
+{{- end}}
+	return d
+}
+{{- end}}
+
+func (d *{{.TypeName}}) rebase(from, to Field[any]) {
+	d.Field.rebase(from, to)
+{{- range .Fields}}
+	d.{{.FieldName}}.rebase(from, to)
+{{- end}}
+}
+{{end}}
+// {{.Name}}Metamodel is the type of {{.Name}}_.
+type {{.Name}}Metamodel struct {
 	TableName string
+{{- with tagNames .}}
+	TagNames  TagNames
+{{- end}}
+{{- range .Fields}}
+	{{.FieldName}} {{fieldType $struct.Name .}}
+{{- end}}
+}
+{{if and (not (hasField . "As")) (ownable .)}}
+// As returns a copy of {{.Name}}_ whose fields are qualified by alias, for
+// self-joins and queries that reference the table more than once.
+// Example: {{.Name}}_.As("t")
+func (m {{.Name}}Metamodel) As(alias string) {{.Name}}Metamodel {
 {{- range .Fields}}
-	{{.FieldName}} Field
+	m.{{.FieldName}} = m.{{.FieldName}}.WithOwner(alias)
 {{- end}}
-}{
+	return m
+}
+{{end}}
+// {{.Name}}_ contains field name constants for {{.StructName}}
+var {{.Name}}_ = {{.Name}}Metamodel{
 	TableName: "{{$tableName}}",
+{{- with tagNames .}}
+	TagNames: {{.}},
+{{- end}}
 {{- range .Fields}}
-	{{.FieldName}}: Field{FieldName: "{{.TagName}}", TableName: "{{$tableName}}"},
+	{{.FieldName}}: {{fieldValue $struct.Name . $tableName}},
 {{- end}}
 }
+{{- with .Indexes}}
+
+func init() {
+	registerMgoIndexes({{$struct.Name}}_.TableName, {{$struct.Name}}_.Indexes)
+}
+
+// Indexes returns the MongoDB indexes declared on {{$struct.StructName}}, created
+// in the {{$struct.Name}}_.TableName collection by EnsureIndexes.
+func (m {{$struct.Name}}Metamodel) Indexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+{{- range .}}
+		{
+			Keys: bson.D{
+{{- range .Keys}}
+				{Key: m.{{.Field}}.mgoKey(), Value: {{if .Desc}}-1{{else}}1{{end}}},
+{{- end}}
+			},
+			Options: options.Index()
+{{- if .Unique}}.SetUnique(true){{end}}
+{{- if .Sparse}}.SetSparse(true){{end}}
+{{- with .TTL}}.SetExpireAfterSeconds({{.}}){{end}}
+{{- with .Partial}}.SetPartialFilterExpression({{.}}){{end}}
+{{- with .Name}}.SetName({{printf "%q" .}}){{end}},
+		},
+{{- end}}
+	}
+}
+{{- end}}
 {{end}}
 `
 
@@ -26,9 +131,68 @@ const commonTemplate = `// Code generated by metamodel. DO NOT EDIT.
 
 package {{.PackageName}}
 
+import "fmt"
+
 const (
 	Comma      string = ", "
 )
+
+// Struct tags a metamodel generated with several tags translates between.
+const (
+	TagJSON = "json"
+	TagBSON = "bson"
+	TagGORM = "gorm"
+)
+
+// TagNames holds, for every field of a struct, its name under each struct tag.
+// Nested document fields are listed with their dotted path.
+type TagNames []map[string]string
+
+// Translate maps the name of a field under one tag to its name under another.
+// Example: User_.TagNames.Translate(TagJSON, TagGORM, "emailAddress") → "email_address", true
+func (t TagNames) Translate(from, to, name string) (string, bool) {
+	for _, names := range t {
+		if names[from] == name && name != "" {
+			translated, ok := names[to]
+			return translated, ok && translated != ""
+		}
+	}
+	return "", false
+}
+
+// TranslateAll maps several names at once, reporting the first unknown one.
+// Example: sort parameters of an API request to their storage columns.
+func (t TagNames) TranslateAll(from, to string, names ...string) ([]string, error) {
+	translated := make([]string, 0, len(names))
+	for _, name := range names {
+		n, ok := t.Translate(from, to, name)
+		if !ok {
+			return nil, fmt.Errorf("no %s name for %s field %q", to, from, name)
+		}
+		translated = append(translated, n)
+	}
+	return translated, nil
+}
+
+// JSONToColumn maps a json field name to its gorm column.
+func (t TagNames) JSONToColumn(name string) (string, bool) {
+	return t.Translate(TagJSON, TagGORM, name)
+}
+
+// ColumnToJSON maps a gorm column to its json field name.
+func (t TagNames) ColumnToJSON(name string) (string, bool) {
+	return t.Translate(TagGORM, TagJSON, name)
+}
+
+// JSONToBSON maps a json field name to its bson key.
+func (t TagNames) JSONToBSON(name string) (string, bool) {
+	return t.Translate(TagJSON, TagBSON, name)
+}
+
+// BSONToJSON maps a bson key to its json field name.
+func (t TagNames) BSONToJSON(name string) (string, bool) {
+	return t.Translate(TagBSON, TagJSON, name)
+}
 `
 
 const gormFieldTemplate = `// Code generated by metamodel. DO NOT EDIT.
@@ -42,139 +206,517 @@ import (
 	"gorm.io/gorm/clause"
 )
 
-// Field represents a database column with its name and table name.
-type Field struct {
+// Field represents a database column with its name and table name. T is the
+// Go type of the struct field, so operands of the wrong type do not compile;
+// it is any for types the metamodel package cannot name.
+// Columns of a known Go type are generated as StringField, BoolField,
+// NumberField, TimeField or SliceField, adding the operators of that type.
+// JSON, BSON and Column hold the field's name under each struct tag when the
+// metamodel is generated from several tags (-tag=json,bson,gorm).
+// Alias, when set, is the table alias the column is qualified with in SQL.
+type Field[T any] struct {
 	FieldName string
 	TableName string
+	Alias     string
+	JSON      string
+	BSON      string
+	Column    string
 }
 
 // Equal generates a GORM equality condition: "column = ?"
-func (f Field) Equal(val any) clause.Eq {
-	return clause.Eq{Column: f.FieldName, Value: val}
+func (f Field[T]) Equal(val T) clause.Eq {
+	return clause.Eq{Column: f, Value: val}
 }
 
-func (f Field) EqualString(val any) string {
-	return fmt.Sprintf(" %s = %v ", f.FieldName, val)
+// EqualString is the raw SQL form of Equal. Unsafe with untrusted input:
+// values are interpolated without quoting or escaping.
+func (f Field[T]) EqualString(val T) string {
+	return fmt.Sprintf(" %s = %v ", f.quoted(), val)
 }
 
 // NotEqual generates a GORM not-equal condition: "column <> ?"
-func (f Field) NotEqual(val any) clause.Neq {
-	return clause.Neq{Column: f.FieldName, Value: val}
+func (f Field[T]) NotEqual(val T) clause.Neq {
+	return clause.Neq{Column: f, Value: val}
 }
 
-func (f Field) NotEqualString(val any) string {
-	return fmt.Sprintf(" %s <> %v ", f.FieldName, val)
+// NotEqualString is the raw SQL form of NotEqual. Unsafe with untrusted input:
+// values are interpolated without quoting or escaping.
+func (f Field[T]) NotEqualString(val T) string {
+	return fmt.Sprintf(" %s <> %v ", f.quoted(), val)
 }
 
 // In generates a GORM IN condition: "column IN (?)"
-func (f Field) In(vals ...any) clause.IN {
-	return clause.IN{Column: clause.Column{Name: f.FieldName}, Values: vals}
+func (f Field[T]) In(vals ...T) clause.IN {
+	values := make([]any, len(vals))
+	for i, v := range vals {
+		values[i] = v
+	}
+	return clause.IN{Column: f, Values: values}
+}
+
+// InQuery generates a GORM condition on the rows of a subquery:
+// "column IN (SELECT ...)"
+// Example: GormTest_.Id.InQuery(NewQueryBuilder(EmbeddedEntity_.TableName).Select(EmbeddedEntity_.ParentId))
+func (f Field[T]) InQuery(qb *QueryBuilder) clause.Expr {
+	return clause.Expr{SQL: "? IN ?", Vars: []any{f, Subquery(qb)}}
 }
 
-func (f Field) InString(vals ...any) string {
+// InString is the raw SQL form of In. Unsafe with untrusted input:
+// values are interpolated without quoting or escaping.
+func (f Field[T]) InString(vals ...T) string {
 	var valStrs []string
 	for _, v := range vals {
-		valStrs = append(valStrs, fmt.Sprintf("%s", v))
+		valStrs = append(valStrs, fmt.Sprintf("%v", v))
+	}
+	return fmt.Sprintf(" %s IN (%s) ", f.quoted(), strings.Join(valStrs, ", "))
+}
+
+// Asc returns an ORDER BY ascending expression. Its column is qualified only
+// by the alias set with WithOwner or WithDefaultOwner, which a QueryBuilder
+// keeps in joined queries.
+func (f Field[T]) Asc() clause.OrderByColumn {
+	return clause.OrderByColumn{Column: clause.Column{Table: f.Alias, Name: f.column()}}
+}
+
+func (f Field[T]) AscString() string {
+	return fmt.Sprintf(" %s ASC ", f.quoted())
+}
+
+// Desc returns an ORDER BY descending expression, qualified like Asc.
+func (f Field[T]) Desc() clause.OrderByColumn {
+	return clause.OrderByColumn{Column: clause.Column{Table: f.Alias, Name: f.column()}, Desc: true}
+}
+
+func (f Field[T]) DescString() string {
+	return fmt.Sprintf(" %s DESC ", f.quoted())
+}
+
+// Build writes the column, making a Field usable as a gorm clause.Expression,
+// e.g. in QueryBuilder.Select or GroupBy. Conditions reference the Field
+// itself as their column, so a QueryBuilder with joins can qualify it with
+// its table; gorm writes the column name returned by String.
+func (f Field[T]) Build(builder clause.Builder) {
+	column := clause.Column{Table: f.Alias, Name: f.column()}
+	if q, ok := builder.(columnQualifier); ok && f.Alias == "" {
+		column.Table = q.qualifier(f.TableName)
+	}
+	builder.WriteQuoted(column)
+}
+
+// String returns the raw column name, qualified by the alias if any.
+func (f Field[T]) String() string {
+	if f.Alias != "" {
+		return f.Alias + "." + f.column()
+	}
+	return f.column()
+}
+
+// column is the name of the field's SQL column: its gorm name when the
+// metamodel was generated from several tags, its FieldName otherwise.
+func (f Field[T]) column() string {
+	if f.Column != "" {
+		return f.Column
+	}
+	return f.FieldName
+}
+
+func (f Field[T]) As(val any) string {
+	return fmt.Sprintf(" %s as %v ", f.quoted(), val)
+}
+
+// quoted returns the column name quoted for DefaultDialect, as used by the
+// *String helpers.
+func (f Field[T]) quoted() string {
+	return DefaultDialect.QuoteIdentifier(f.String())
+}
+
+// WithOwner returns the field qualified by the table or alias val, replacing
+// any earlier owner.
+// Example: GormTest_.Id.WithOwner("g").String() → "g.id"
+func (f Field[T]) WithOwner(val string) Field[T] {
+	f.Alias = strings.TrimSpace(val)
+	return f
+}
+
+// WithOwnerString returns the column qualified by the table or alias val,
+// replacing any earlier owner like WithOwner.
+func (f Field[T]) WithOwnerString(val string) string {
+	return fmt.Sprintf(" %s ", f.WithOwner(val).quoted())
+}
+
+func (f Field[T]) WithDefaultOwnerString() string {
+	return fmt.Sprintf(" %s ", f.WithDefaultOwner().quoted())
+}
+
+// WithDefaultOwner returns the field qualified by its own table.
+func (f Field[T]) WithDefaultOwner() Field[T] {
+	f.Alias = f.TableName
+	return f
+}
+
+// FieldRef is implemented by every generated field, whatever its Go type, so
+// that fields can be compared with each other.
+type FieldRef interface {
+	clause.Expression
+	columnRef() columnRef
+}
+
+func (f Field[T]) columnRef() columnRef {
+	return columnRef{table: f.TableName, alias: f.Alias, name: f.column()}
+}
+
+// columnRef is a column always written qualified: by the field's alias, or by
+// its table or the alias the table has in a QueryBuilder.
+type columnRef struct {
+	table string
+	alias string
+	name  string
+}
+
+func (c columnRef) owner(builder clause.Builder) string {
+	if c.alias != "" {
+		return c.alias
+	}
+	if q, ok := builder.(columnQualifier); ok {
+		return q.alias(c.table)
+	}
+	return c.table
+}
+
+func (c columnRef) Build(builder clause.Builder) {
+	builder.WriteQuoted(clause.Column{Table: c.owner(builder), Name: c.name})
+}
+
+func (c columnRef) String() string {
+	if owner := c.owner(nil); owner != "" {
+		return DefaultDialect.QuoteIdentifier(owner) + "." + DefaultDialect.QuoteIdentifier(c.name)
 	}
-	return fmt.Sprintf(" %s IN (%s) ", f.FieldName, strings.Join(valStrs, ", "))
+	return DefaultDialect.QuoteIdentifier(c.name)
+}
+
+// compareFields generates the condition "left op right" between two columns.
+func compareFields(left FieldRef, op string, right FieldRef) clause.Expr {
+	return clause.Expr{SQL: "? " + op + " ?", Vars: []any{left.columnRef(), right.columnRef()}}
+}
+
+func compareFieldsString(left FieldRef, op string, right FieldRef) string {
+	return fmt.Sprintf(" %s %s %s ", left.columnRef(), op, right.columnRef())
+}
+
+// EqField generates a GORM condition comparing two columns: "table.column = other_table.column"
+// Example: GormTest_.Id.EqField(EmbeddedEntity_.ParentId)
+func (f Field[T]) EqField(other FieldRef) clause.Expr {
+	return compareFields(f, "=", other)
+}
+
+func (f Field[T]) EqFieldString(other FieldRef) string {
+	return compareFieldsString(f, "=", other)
+}
+
+// NeField generates a GORM condition comparing two columns: "table.column <> other_table.column"
+func (f Field[T]) NeField(other FieldRef) clause.Expr {
+	return compareFields(f, "<>", other)
+}
+
+func (f Field[T]) NeFieldString(other FieldRef) string {
+	return compareFieldsString(f, "<>", other)
+}
+
+// StringField is a column holding text.
+type StringField[T any] struct {
+	Field[T]
+}
+
+func (f StringField[T]) WithOwner(val string) StringField[T] {
+	f.Field = f.Field.WithOwner(val)
+	return f
+}
+
+func (f StringField[T]) WithDefaultOwner() StringField[T] {
+	f.Field = f.Field.WithDefaultOwner()
+	return f
+}
+
+// Like generates a GORM pattern condition: "column LIKE ?"
+// Example: User_.Name.Like("nam%")
+func (f StringField[T]) Like(pattern string) clause.Like {
+	return clause.Like{Column: f.Field, Value: pattern}
+}
+
+// LikeString is the raw SQL form of Like. Unsafe with untrusted input:
+// values are interpolated without quoting or escaping.
+func (f StringField[T]) LikeString(pattern string) string {
+	return fmt.Sprintf(" %s LIKE '%s' ", f.quoted(), pattern)
+}
+
+// NotLike generates a GORM negated pattern condition: "column NOT LIKE ?"
+func (f StringField[T]) NotLike(pattern string) clause.Expression {
+	return clause.Not(clause.Like{Column: f.Field, Value: pattern})
+}
+
+// NotLikeString is the raw SQL form of NotLike. Unsafe with untrusted input:
+// values are interpolated without quoting or escaping.
+func (f StringField[T]) NotLikeString(pattern string) string {
+	return fmt.Sprintf(" %s NOT LIKE '%s' ", f.quoted(), pattern)
+}
+
+// BoolField is a boolean column.
+type BoolField[T any] struct {
+	Field[T]
+}
+
+func (f BoolField[T]) WithOwner(val string) BoolField[T] {
+	f.Field = f.Field.WithOwner(val)
+	return f
+}
+
+func (f BoolField[T]) WithDefaultOwner() BoolField[T] {
+	f.Field = f.Field.WithDefaultOwner()
+	return f
+}
+
+// IsTrue generates a GORM equality condition for boolean true.
+func (f BoolField[T]) IsTrue() clause.Eq {
+	return clause.Eq{Column: f.Field, Value: true}
+}
+
+func (f BoolField[T]) IsTrueString() string {
+	return fmt.Sprintf(" %s = %s ", f.quoted(), DefaultDialect.BoolLiteral(true))
+}
+
+// IsFalse generates a GORM equality condition for boolean false.
+func (f BoolField[T]) IsFalse() clause.Eq {
+	return clause.Eq{Column: f.Field, Value: false}
+}
+
+func (f BoolField[T]) IsFalseString() string {
+	return fmt.Sprintf(" %s = %s ", f.quoted(), DefaultDialect.BoolLiteral(false))
+}
+
+// NumberField is a numeric column, supporting ordering and ranges.
+type NumberField[T any] struct {
+	Field[T]
+}
+
+func (f NumberField[T]) WithOwner(val string) NumberField[T] {
+	f.Field = f.Field.WithOwner(val)
+	return f
+}
+
+func (f NumberField[T]) WithDefaultOwner() NumberField[T] {
+	f.Field = f.Field.WithDefaultOwner()
+	return f
 }
 
 // Gt generates a GORM greater-than condition: "column > ?"
-func (f Field) Gt(val any) clause.Gt {
-	return clause.Gt{Column: f.FieldName, Value: val}
+func (f NumberField[T]) Gt(val T) clause.Gt {
+	return clause.Gt{Column: f.Field, Value: val}
 }
 
-func (f Field) GtString(val any) string {
-	return fmt.Sprintf(" %s > %v ", f.FieldName, val)
+// GtString is the raw SQL form of Gt. Unsafe with untrusted input:
+// values are interpolated without quoting or escaping.
+func (f NumberField[T]) GtString(val T) string {
+	return fmt.Sprintf(" %s > %v ", f.quoted(), val)
 }
 
 // Gte generates a GORM greater-than-or-equal condition: "column >= ?"
-func (f Field) Gte(val any) clause.Gte {
-	return clause.Gte{Column: f.FieldName, Value: val}
+func (f NumberField[T]) Gte(val T) clause.Gte {
+	return clause.Gte{Column: f.Field, Value: val}
 }
 
-func (f Field) GteString(val any) string {
-	return fmt.Sprintf(" %s >= %v ", f.FieldName, val)
+// GteString is the raw SQL form of Gte. Unsafe with untrusted input:
+// values are interpolated without quoting or escaping.
+func (f NumberField[T]) GteString(val T) string {
+	return fmt.Sprintf(" %s >= %v ", f.quoted(), val)
 }
 
 // Lt generates a GORM less-than condition: "column < ?"
-func (f Field) Lt(val any) clause.Lt {
-	return clause.Lt{Column: f.FieldName, Value: val}
+func (f NumberField[T]) Lt(val T) clause.Lt {
+	return clause.Lt{Column: f.Field, Value: val}
 }
 
-func (f Field) LtString(val any) string {
-	return fmt.Sprintf(" %s < %v ", f.FieldName, val)
+// LtString is the raw SQL form of Lt. Unsafe with untrusted input:
+// values are interpolated without quoting or escaping.
+func (f NumberField[T]) LtString(val T) string {
+	return fmt.Sprintf(" %s < %v ", f.quoted(), val)
 }
 
 // Lte generates a GORM less-than-or-equal condition: "column <= ?"
-func (f Field) Lte(val any) clause.Lte {
-	return clause.Lte{Column: f.FieldName, Value: val}
+func (f NumberField[T]) Lte(val T) clause.Lte {
+	return clause.Lte{Column: f.Field, Value: val}
 }
 
-func (f Field) LteString(val any) string {
-	return fmt.Sprintf(" %s <= %v ", f.FieldName, val)
+// LteString is the raw SQL form of Lte. Unsafe with untrusted input:
+// values are interpolated without quoting or escaping.
+func (f NumberField[T]) LteString(val T) string {
+	return fmt.Sprintf(" %s <= %v ", f.quoted(), val)
 }
 
-// IsTrue generates a GORM equality condition for boolean true.
-func (f Field) IsTrue() clause.Eq {
-	return clause.Eq{Column: f.FieldName, Value: true}
+// Between generates a GORM range condition: "column BETWEEN ? AND ?"
+func (f NumberField[T]) Between(from, to T) clause.Expr {
+	return clause.Expr{SQL: "? BETWEEN ? AND ?", Vars: []any{f.Field, from, to}}
 }
 
-func (f Field) IsTrueString() string {
-	return fmt.Sprintf(" %s = true ", f.FieldName)
+// BetweenString is the raw SQL form of Between. Unsafe with untrusted input:
+// values are interpolated without quoting or escaping.
+func (f NumberField[T]) BetweenString(from, to T) string {
+	return fmt.Sprintf(" %s BETWEEN %v AND %v ", f.quoted(), from, to)
 }
 
-// IsFalse generates a GORM equality condition for boolean false.
-func (f Field) IsFalse() clause.Eq {
-	return clause.Eq{Column: f.FieldName, Value: false}
+// GtField generates a GORM greater-than condition between two columns: "table.column > other_table.column"
+func (f NumberField[T]) GtField(other FieldRef) clause.Expr {
+	return compareFields(f, ">", other)
 }
 
-func (f Field) IsFalseString() string {
-	return fmt.Sprintf(" %s = false ", f.FieldName)
+func (f NumberField[T]) GtFieldString(other FieldRef) string {
+	return compareFieldsString(f, ">", other)
 }
 
-// Asc returns an ORDER BY ascending expression.
-func (f Field) Asc() clause.OrderByColumn {
-	return clause.OrderByColumn{Column: clause.Column{Name: f.FieldName}}
+// GteField generates a GORM greater-than-or-equal condition between two columns: "table.column >= other_table.column"
+func (f NumberField[T]) GteField(other FieldRef) clause.Expr {
+	return compareFields(f, ">=", other)
 }
 
-func (f Field) AscString() string {
-	return fmt.Sprintf(" %s ASC ", f.FieldName)
+func (f NumberField[T]) GteFieldString(other FieldRef) string {
+	return compareFieldsString(f, ">=", other)
 }
 
-// Desc returns an ORDER BY descending expression.
-func (f Field) Desc() clause.OrderByColumn {
-	return clause.OrderByColumn{Column: clause.Column{Name: f.FieldName}, Desc: true}
+// LtField generates a GORM less-than condition between two columns: "table.column < other_table.column"
+func (f NumberField[T]) LtField(other FieldRef) clause.Expr {
+	return compareFields(f, "<", other)
 }
 
-func (f Field) DescString() string {
-	return fmt.Sprintf(" %s DESC ", f.FieldName)
+func (f NumberField[T]) LtFieldString(other FieldRef) string {
+	return compareFieldsString(f, "<", other)
 }
 
-// String returns the raw column name.
-func (f Field) String() string {
-	return f.FieldName
+// LteField generates a GORM less-than-or-equal condition between two columns: "table.column <= other_table.column"
+func (f NumberField[T]) LteField(other FieldRef) clause.Expr {
+	return compareFields(f, "<=", other)
+}
+
+func (f NumberField[T]) LteFieldString(other FieldRef) string {
+	return compareFieldsString(f, "<=", other)
 }
 
-func (f Field) As(val any) string {
-	return fmt.Sprintf(" %s as %v ", f.FieldName, val)
+// TimeField is a date or time column, supporting ordering and ranges.
+type TimeField[T any] struct {
+	Field[T]
 }
 
-func (f Field) WithOwner(val string) Field {
-	f.FieldName = fmt.Sprintf(" %v.%s ", val, f.FieldName)
+func (f TimeField[T]) WithOwner(val string) TimeField[T] {
+	f.Field = f.Field.WithOwner(val)
 	return f
 }
 
-func (f Field) WithOwnerString(val string) string {
-	return fmt.Sprintf(" %s.%s ", val, f.FieldName)
+func (f TimeField[T]) WithDefaultOwner() TimeField[T] {
+	f.Field = f.Field.WithDefaultOwner()
+	return f
+}
+
+// Gt generates a GORM greater-than condition: "column > ?"
+func (f TimeField[T]) Gt(val T) clause.Gt {
+	return clause.Gt{Column: f.Field, Value: val}
 }
 
-func (f Field) WithDefaultOwnerString() string {
-	return fmt.Sprintf(" %s.%s ", f.TableName, f.FieldName)
+// GtString is the raw SQL form of Gt. Unsafe with untrusted input:
+// values are interpolated without quoting or escaping.
+func (f TimeField[T]) GtString(val T) string {
+	return fmt.Sprintf(" %s > %v ", f.quoted(), val)
 }
 
-func (f Field) WithDefaultOwner() Field {
-	f.FieldName = fmt.Sprintf(" %s.%s ", f.TableName, f.FieldName)
+// Gte generates a GORM greater-than-or-equal condition: "column >= ?"
+func (f TimeField[T]) Gte(val T) clause.Gte {
+	return clause.Gte{Column: f.Field, Value: val}
+}
+
+// GteString is the raw SQL form of Gte. Unsafe with untrusted input:
+// values are interpolated without quoting or escaping.
+func (f TimeField[T]) GteString(val T) string {
+	return fmt.Sprintf(" %s >= %v ", f.quoted(), val)
+}
+
+// Lt generates a GORM less-than condition: "column < ?"
+func (f TimeField[T]) Lt(val T) clause.Lt {
+	return clause.Lt{Column: f.Field, Value: val}
+}
+
+// LtString is the raw SQL form of Lt. Unsafe with untrusted input:
+// values are interpolated without quoting or escaping.
+func (f TimeField[T]) LtString(val T) string {
+	return fmt.Sprintf(" %s < %v ", f.quoted(), val)
+}
+
+// Lte generates a GORM less-than-or-equal condition: "column <= ?"
+func (f TimeField[T]) Lte(val T) clause.Lte {
+	return clause.Lte{Column: f.Field, Value: val}
+}
+
+// LteString is the raw SQL form of Lte. Unsafe with untrusted input:
+// values are interpolated without quoting or escaping.
+func (f TimeField[T]) LteString(val T) string {
+	return fmt.Sprintf(" %s <= %v ", f.quoted(), val)
+}
+
+// Between generates a GORM range condition: "column BETWEEN ? AND ?"
+func (f TimeField[T]) Between(from, to T) clause.Expr {
+	return clause.Expr{SQL: "? BETWEEN ? AND ?", Vars: []any{f.Field, from, to}}
+}
+
+// BetweenString is the raw SQL form of Between. Unsafe with untrusted input:
+// values are interpolated without quoting or escaping.
+func (f TimeField[T]) BetweenString(from, to T) string {
+	return fmt.Sprintf(" %s BETWEEN %v AND %v ", f.quoted(), from, to)
+}
+
+// GtField generates a GORM greater-than condition between two columns: "table.column > other_table.column"
+func (f TimeField[T]) GtField(other FieldRef) clause.Expr {
+	return compareFields(f, ">", other)
+}
+
+func (f TimeField[T]) GtFieldString(other FieldRef) string {
+	return compareFieldsString(f, ">", other)
+}
+
+// GteField generates a GORM greater-than-or-equal condition between two columns: "table.column >= other_table.column"
+func (f TimeField[T]) GteField(other FieldRef) clause.Expr {
+	return compareFields(f, ">=", other)
+}
+
+func (f TimeField[T]) GteFieldString(other FieldRef) string {
+	return compareFieldsString(f, ">=", other)
+}
+
+// LtField generates a GORM less-than condition between two columns: "table.column < other_table.column"
+func (f TimeField[T]) LtField(other FieldRef) clause.Expr {
+	return compareFields(f, "<", other)
+}
+
+func (f TimeField[T]) LtFieldString(other FieldRef) string {
+	return compareFieldsString(f, "<", other)
+}
+
+// LteField generates a GORM less-than-or-equal condition between two columns: "table.column <= other_table.column"
+func (f TimeField[T]) LteField(other FieldRef) clause.Expr {
+	return compareFields(f, "<=", other)
+}
+
+func (f TimeField[T]) LteFieldString(other FieldRef) string {
+	return compareFieldsString(f, "<=", other)
+}
+
+// SliceField is a column holding a list, such as a Postgres array or a serialized slice.
+type SliceField[T any] struct {
+	Field[T]
+}
+
+func (f SliceField[T]) WithOwner(val string) SliceField[T] {
+	f.Field = f.Field.WithOwner(val)
+	return f
+}
+
+func (f SliceField[T]) WithDefaultOwner() SliceField[T] {
+	f.Field = f.Field.WithDefaultOwner()
 	return f
 }
 
@@ -186,15 +728,14 @@ func (f Field) WithDefaultOwner() Field {
 //	    CategoryEntity_.Value.As("category_id"),
 //	)).Where(...).GroupBy(...).Having(...).Order(...)
 //
-// Example full query:
+// Aggregates are expressions rather than strings; select them with
+// placeholders (see Expr):
 //
-//	db.Select(Columns(
-//	    GormTest_.FeatureName.String(),
-//	    "COUNT(*) as total",
-//	)).Where(GormTest_.Status.Equal("active")).
-//	  GroupBy(GormTest_.FeatureName.String()).
-//	  Having(clause.Gt{Column: "COUNT(*)", Value: 5}).
-//	  Order(GormTest_.CreatedAt.Desc()).
+//	db.Select("?, ?", GormTest_.FeatureName, CountAll().As("total")).
+//	  Where(GormTest_.IsActive.IsTrue()).
+//	  Group(GormTest_.FeatureName.String()).
+//	  Having(CountAll().Gt(5)).
+//	  Order(CountAll().Desc()).
 //	  Find(&results)
 func Columns(cols ...string) string {
 	return strings.Join(cols, ", ")
@@ -223,97 +764,6 @@ func Or(conditions ...clause.Expression) clause.OrConditions {
 func OrString(conditions ...string) string {
 	return strings.Join(conditions, " OR ")
 }
-
-// QueryBuilder helps construct complete SQL SELECT statements.
-type QueryBuilder struct {
-	selectCols []string
-	fromTable  string
-	whereConds []string
-	groupByCols []string
-	havingConds []string
-	orderByCols []string
-}
-
-// NewQueryBuilder creates a new QueryBuilder for the given table.
-func NewQueryBuilder(tableName string) *QueryBuilder {
-	return &QueryBuilder{
-		fromTable: tableName,
-	}
-}
-
-// Select adds columns to the SELECT clause.
-func (qb *QueryBuilder) Select(cols ...string) *QueryBuilder {
-	qb.selectCols = append(qb.selectCols, cols...)
-	return qb
-}
-
-// Where adds conditions to the WHERE clause.
-func (qb *QueryBuilder) Where(conditions ...string) *QueryBuilder {
-	qb.whereConds = append(qb.whereConds, conditions...)
-	return qb
-}
-
-// GroupBy adds columns to the GROUP BY clause.
-func (qb *QueryBuilder) GroupBy(cols ...string) *QueryBuilder {
-	qb.groupByCols = append(qb.groupByCols, cols...)
-	return qb
-}
-
-// Having adds conditions to the HAVING clause.
-func (qb *QueryBuilder) Having(conditions ...string) *QueryBuilder {
-	qb.havingConds = append(qb.havingConds, conditions...)
-	return qb
-}
-
-// OrderBy adds columns to the ORDER BY clause.
-func (qb *QueryBuilder) OrderBy(cols ...string) *QueryBuilder {
-	qb.orderByCols = append(qb.orderByCols, cols...)
-	return qb
-}
-
-// Build constructs and returns the complete SQL SELECT statement as a string.
-// Expected format: "SELECT a,b,c FROM table WHERE ... GROUP BY ... HAVING ... ORDER BY ..."
-func (qb *QueryBuilder) Build() string {
-	var query strings.Builder
-	
-	// SELECT clause
-	query.WriteString("SELECT ")
-	if len(qb.selectCols) > 0 {
-		query.WriteString(strings.Join(qb.selectCols, ","))
-	} else {
-		query.WriteString("*")
-	}
-	
-	// FROM clause
-	query.WriteString(" FROM ")
-	query.WriteString(qb.fromTable)
-	
-	// WHERE clause
-	if len(qb.whereConds) > 0 {
-		query.WriteString(" WHERE ")
-		query.WriteString(strings.Join(qb.whereConds, " AND "))
-	}
-	
-	// GROUP BY clause
-	if len(qb.groupByCols) > 0 {
-		query.WriteString(" GROUP BY ")
-		query.WriteString(strings.Join(qb.groupByCols, ","))
-	}
-	
-	// HAVING clause
-	if len(qb.havingConds) > 0 {
-		query.WriteString(" HAVING ")
-		query.WriteString(strings.Join(qb.havingConds, " AND "))
-	}
-	
-	// ORDER BY clause
-	if len(qb.orderByCols) > 0 {
-		query.WriteString(" ORDER BY ")
-		query.WriteString(strings.Join(qb.orderByCols, ","))
-	}
-	
-	return query.String()
-}
 `
 
 const mongoFieldTemplate = `// Code generated by metamodel. DO NOT EDIT.
@@ -321,62 +771,36 @@ const mongoFieldTemplate = `// Code generated by metamodel. DO NOT EDIT.
 package [[.PackageName]]
 
 import (
+	"strconv"
+	"strings"
+
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 // MgoEq generates a MongoDB equality filter: { field: { $eq: val } }
 // Example: User_.Name.MgoEq("test") → bson.D{{"name", bson.D{{"$eq", "test"}}}}
-func (f Field) MgoEq(val any) bson.D {
-	return bson.D{{Key: f.FieldName, Value: bson.D{{Key: "$eq", Value: val}}}}
+func (f Field[T]) MgoEq(val T) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$eq", Value: val}}}}
 }
 
 // MgoNe generates a MongoDB not-equal filter: { field: { $ne: val } }
-func (f Field) MgoNe(val any) bson.D {
-	return bson.D{{Key: f.FieldName, Value: bson.D{{Key: "$ne", Value: val}}}}
-}
-
-// MgoGt generates a MongoDB greater-than filter: { field: { $gt: val } }
-func (f Field) MgoGt(val any) bson.D {
-	return bson.D{{Key: f.FieldName, Value: bson.D{{Key: "$gt", Value: val}}}}
-}
-
-// MgoGte generates a MongoDB greater-than-or-equal filter: { field: { $gte: val } }
-func (f Field) MgoGte(val any) bson.D {
-	return bson.D{{Key: f.FieldName, Value: bson.D{{Key: "$gte", Value: val}}}}
-}
-
-// MgoLt generates a MongoDB less-than filter: { field: { $lt: val } }
-func (f Field) MgoLt(val any) bson.D {
-	return bson.D{{Key: f.FieldName, Value: bson.D{{Key: "$lt", Value: val}}}}
-}
-
-// MgoLte generates a MongoDB less-than-or-equal filter: { field: { $lte: val } }
-func (f Field) MgoLte(val any) bson.D {
-	return bson.D{{Key: f.FieldName, Value: bson.D{{Key: "$lte", Value: val}}}}
+func (f Field[T]) MgoNe(val T) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$ne", Value: val}}}}
 }
 
 // MgoIn generates a MongoDB $in filter: { field: { $in: [vals...] } }
-func (f Field) MgoIn(vals ...any) bson.D {
-	return bson.D{{Key: f.FieldName, Value: bson.D{{Key: "$in", Value: vals}}}}
+func (f Field[T]) MgoIn(vals ...T) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$in", Value: vals}}}}
 }
 
 // MgoNin generates a MongoDB $nin filter: { field: { $nin: [vals...] } }
-func (f Field) MgoNin(vals ...any) bson.D {
-	return bson.D{{Key: f.FieldName, Value: bson.D{{Key: "$nin", Value: vals}}}}
+func (f Field[T]) MgoNin(vals ...T) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$nin", Value: vals}}}}
 }
 
 // MgoExists generates a MongoDB $exists filter: { field: { $exists: exists } }
-func (f Field) MgoExists(exists bool) bson.D {
-	return bson.D{{Key: f.FieldName, Value: bson.D{{Key: "$exists", Value: exists}}}}
-}
-
-// MgoRegex generates a MongoDB $regex filter: { field: { $regex: pattern, $options: opts } }
-// Example: User_.Name.MgoRegex("^test", "i")
-func (f Field) MgoRegex(pattern string, opts string) bson.D {
-	return bson.D{{Key: f.FieldName, Value: bson.D{
-		{Key: "$regex", Value: pattern},
-		{Key: "$options", Value: opts},
-	}}}
+func (f Field[T]) MgoExists(exists bool) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$exists", Value: exists}}}}
 }
 
 // MgoAnd combines multiple MongoDB filters with $and:
@@ -413,20 +837,265 @@ func MgoNor(filters ...bson.D) bson.D {
 
 // MgoNot wraps a field condition with $not: { field: { $not: { op: val } } }
 // Example: User_.Age.MgoNot(User_.Age.MgoGt(18))
-func (f Field) MgoNot(filter bson.D) bson.D {
+func (f Field[T]) MgoNot(filter bson.D) bson.D {
 	if len(filter) == 0 {
 		return bson.D{}
 	}
-	return bson.D{{Key: f.FieldName, Value: bson.D{{Key: "$not", Value: filter[0].Value}}}}
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$not", Value: filter[0].Value}}}}
 }
 
 // MgoAsc generates a MongoDB ascending sort document: { field: 1 }
-func (f Field) MgoAsc() bson.D {
-	return bson.D{{Key: f.FieldName, Value: 1}}
+func (f Field[T]) MgoAsc() bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: 1}}
 }
 
 // MgoDesc generates a MongoDB descending sort document: { field: -1 }
-func (f Field) MgoDesc() bson.D {
-	return bson.D{{Key: f.FieldName, Value: -1}}
+func (f Field[T]) MgoDesc() bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: -1}}
+}
+
+// MgoRegex generates a MongoDB $regex filter: { field: { $regex: pattern, $options: opts } }
+// Example: User_.Name.MgoRegex("^test", "i")
+func (f StringField[T]) MgoRegex(pattern string, opts string) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{
+		{Key: "$regex", Value: pattern},
+		{Key: "$options", Value: opts},
+	}}}
+}
+
+// MgoGt generates a MongoDB greater-than filter: { field: { $gt: val } }
+// MongoDB orders values of every type, so ranges apply to strings and
+// ObjectIDs as well: Scenarios_.ID.MgoGt(lastID) pages through a collection.
+func (f Field[T]) MgoGt(val T) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$gt", Value: val}}}}
+}
+
+// MgoGte generates a MongoDB greater-than-or-equal filter: { field: { $gte: val } }
+func (f Field[T]) MgoGte(val T) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$gte", Value: val}}}}
+}
+
+// MgoLt generates a MongoDB less-than filter: { field: { $lt: val } }
+func (f Field[T]) MgoLt(val T) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$lt", Value: val}}}}
+}
+
+// MgoLte generates a MongoDB less-than-or-equal filter: { field: { $lte: val } }
+func (f Field[T]) MgoLte(val T) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$lte", Value: val}}}}
+}
+
+// mgoKey is the key of the field in MongoDB documents: its bson name when the
+// metamodel was generated from several tags, its FieldName otherwise.
+func (f Field[T]) mgoKey() string {
+	if f.BSON != "" {
+		return f.BSON
+	}
+	return f.FieldName
+}
+
+// MgoUpdate merges update documents into one, grouping the fields of each
+// update operator under a single key, as UpdateOne and UpdateMany expect:
+// { $set: { a: 1, b: 2 }, $inc: { c: 1 } }
+// Example: MgoUpdate(User_.Name.MgoSet("test"), User_.Age.MgoInc(1), User_.UpdatedAt.MgoCurrentDate())
+func MgoUpdate(updates ...bson.D) bson.D {
+	merged := bson.D{}
+	index := make(map[string]int)
+	for _, update := range updates {
+		for _, op := range update {
+			fields, ok := op.Value.(bson.D)
+			if !ok {
+				merged = append(merged, op)
+				continue
+			}
+			i, seen := index[op.Key]
+			if !seen {
+				index[op.Key] = len(merged)
+				merged = append(merged, bson.E{Key: op.Key, Value: append(bson.D{}, fields...)})
+				continue
+			}
+			if group, ok := merged[i].Value.(bson.D); ok {
+				merged[i].Value = append(group, fields...)
+			}
+		}
+	}
+	return merged
+}
+
+// mgoUpdate generates the update document { op: { field: val } }
+func mgoUpdate(op, key string, val any) bson.D {
+	return bson.D{{Key: op, Value: bson.D{{Key: key, Value: val}}}}
+}
+
+// MgoSet generates a MongoDB $set update: { $set: { field: val } }
+func (f Field[T]) MgoSet(val T) bson.D {
+	return mgoUpdate("$set", f.mgoKey(), val)
+}
+
+// MgoUnset generates a MongoDB $unset update, removing the field: { $unset: { field: "" } }
+func (f Field[T]) MgoUnset() bson.D {
+	return mgoUpdate("$unset", f.mgoKey(), "")
+}
+
+// MgoMin generates a MongoDB $min update, lowering the field to val when
+// val is smaller: { $min: { field: val } }
+func (f Field[T]) MgoMin(val T) bson.D {
+	return mgoUpdate("$min", f.mgoKey(), val)
+}
+
+// MgoMax generates a MongoDB $max update, raising the field to val when val
+// is larger: { $max: { field: val } }
+func (f Field[T]) MgoMax(val T) bson.D {
+	return mgoUpdate("$max", f.mgoKey(), val)
+}
+
+// MgoInc generates a MongoDB $inc update: { $inc: { field: delta } }
+func (f NumberField[T]) MgoInc(delta T) bson.D {
+	return mgoUpdate("$inc", f.mgoKey(), delta)
+}
+
+// MgoMul generates a MongoDB $mul update: { $mul: { field: factor } }
+func (f NumberField[T]) MgoMul(factor T) bson.D {
+	return mgoUpdate("$mul", f.mgoKey(), factor)
+}
+
+// MgoCurrentDate generates a MongoDB $currentDate update, setting the field
+// to the server's current date: { $currentDate: { field: true } }
+func (f TimeField[T]) MgoCurrentDate() bson.D {
+	return mgoUpdate("$currentDate", f.mgoKey(), true)
+}
+
+// MgoEq matches arrays holding an element equal to val, or equal to val as a
+// whole: { field: { $eq: val } }
+// Example: Scenarios_.Tags.MgoEq("urgent")
+func (f SliceField[T]) MgoEq(val any) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$eq", Value: val}}}}
+}
+
+// MgoNe matches arrays holding no element equal to val: { field: { $ne: val } }
+func (f SliceField[T]) MgoNe(val any) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$ne", Value: val}}}}
+}
+
+// MgoIn matches arrays holding one of vals: { field: { $in: [vals...] } }
+func (f SliceField[T]) MgoIn(vals ...any) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$in", Value: vals}}}}
+}
+
+// MgoNin matches arrays holding none of vals: { field: { $nin: [vals...] } }
+func (f SliceField[T]) MgoNin(vals ...any) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$nin", Value: vals}}}}
+}
+
+// MgoPush generates a MongoDB $push update appending vals to the array:
+// { $push: { field: val } }, or { $push: { field: { $each: [vals...] } } }
+// for several values.
+func (f SliceField[T]) MgoPush(vals ...any) bson.D {
+	return mgoUpdate("$push", f.mgoKey(), mgoEach(vals))
+}
+
+// MgoAddToSet generates a MongoDB $addToSet update appending the vals the
+// array does not hold yet: { $addToSet: { field: val } }, or with $each.
+func (f SliceField[T]) MgoAddToSet(vals ...any) bson.D {
+	return mgoUpdate("$addToSet", f.mgoKey(), mgoEach(vals))
+}
+
+// MgoPull generates a MongoDB $pull update removing the elements equal to
+// val or matching the condition val: { $pull: { field: val } }
+// Example: User_.Tags.MgoPull("old") or User_.Scores.MgoPull(bson.D{{"$lt", 10}})
+func (f SliceField[T]) MgoPull(val any) bson.D {
+	return mgoUpdate("$pull", f.mgoKey(), val)
+}
+
+// mgoEach returns the single value, or the values under $each.
+func mgoEach(vals []any) any {
+	if len(vals) == 1 {
+		return vals[0]
+	}
+	return bson.D{{Key: "$each", Value: vals}}
+}
+
+// MgoAll matches arrays holding every one of vals: { field: { $all: [vals...] } }
+func (f SliceField[T]) MgoAll(vals ...any) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$all", Value: vals}}}}
+}
+
+// MgoSize matches arrays of exactly n elements: { field: { $size: n } }
+func (f SliceField[T]) MgoSize(n int) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$size", Value: n}}}}
+}
+
+// MgoElemMatch matches arrays holding an element that satisfies all filters:
+// { field: { $elemMatch: { ... } } }. Filters on the fields of an array of
+// documents are made relative to the element:
+// Scenarios_.Items.MgoElemMatch(Scenarios_.Items.Name.MgoEq("a"), Scenarios_.Items.Qty.MgoGt(1))
+// → { items: { $elemMatch: { name: { $eq: "a" }, qty: { $gt: 1 } } } }
+// Operators apply to the elements of arrays of values:
+// Scenarios_.Scores.MgoElemMatch(bson.D{{"$gte", 80}}, bson.D{{"$lt", 85}})
+func (f SliceField[T]) MgoElemMatch(filters ...bson.D) bson.D {
+	prefix := f.mgoKey() + "."
+	cond := bson.D{}
+	for _, filter := range filters {
+		for _, e := range filter {
+			e.Key = strings.TrimPrefix(e.Key, prefix)
+			cond = append(cond, e)
+		}
+	}
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$elemMatch", Value: cond}}}}
+}
+
+// MgoType matches values of the given BSON types, such as "string", "int" or
+// "array": { field: { $type: type } }, or { $type: [types...] } for several.
+func (f Field[T]) MgoType(types ...string) bson.D {
+	var val any = types
+	if len(types) == 1 {
+		val = types[0]
+	}
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$type", Value: val}}}}
+}
+
+// MgoMod matches values whose remainder when divided by divisor is remainder:
+// { field: { $mod: [divisor, remainder] } }
+func (f NumberField[T]) MgoMod(divisor, remainder T) bson.D {
+	return bson.D{{Key: f.mgoKey(), Value: bson.D{{Key: "$mod", Value: bson.A{divisor, remainder}}}}}
+}
+
+// At returns element i of the array field: "field.i"
+// Example: Scenarios_.Tags.At(0).MgoEq("first")
+func (f SliceField[T]) At(i int) Field[any] {
+	return f.element(strconv.Itoa(i))
+}
+
+// Positional returns the element of the array field matched by the filter of
+// an update: "field.$"
+// Example: coll.UpdateOne(ctx, Scenarios_.Tags.MgoEq("old"), Scenarios_.Tags.Positional().MgoSet("new"))
+func (f SliceField[T]) Positional() Field[any] {
+	return f.element("$")
+}
+
+// AllPositional returns every element of the array field, for updates: "field.$[]"
+func (f SliceField[T]) AllPositional() Field[any] {
+	return f.element("$[]")
+}
+
+// element returns the field path of an element of the array field.
+func (f Field[T]) element(index string) Field[any] {
+	e := Field[any](f)
+	e.FieldName += "." + index
+	if e.BSON != "" {
+		e.BSON += "." + index
+	}
+	return e
+}
+
+// rebase moves a field of an array element from under the path of the array,
+// from, to the path of an element, to: "items.name" becomes "items.$.name".
+func (f *Field[T]) rebase(from, to Field[any]) {
+	if rest, ok := strings.CutPrefix(f.FieldName, from.FieldName); ok {
+		f.FieldName = to.FieldName + rest
+	}
+	if rest, ok := strings.CutPrefix(f.BSON, from.BSON); ok && f.BSON != "" {
+		f.BSON = to.BSON + rest
+	}
 }
 `
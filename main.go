@@ -10,11 +10,13 @@ import (
 )
 
 var (
-	source      = flag.String("source", "", "Source file to generate metamodel from (e.g., models.go)")
-	destination = flag.String("destination", "", "Output file for generated code (default: <source>_metamodel.go, e.g., models_metamodel.go)")
-	packageName = flag.String("packageName", "metamodel", "Package name for generated file (default: metamodel_, optional for custome with pattern <packageName>_, e.g., models)")
-	tag         = flag.String("tag", "json", "Specific tag name to generate (optional, e.g., json, bson, gorm)")
-	tableName   = flag.String("tableName", "", "Specific table name to generate (default: <structName>s, optional for custome e.g., users, mock_test)")
+	source        = flag.String("source", "", "Source file, package directory or pattern to generate metamodel from (e.g., models.go, ./repository, ./...)")
+	destination   = flag.String("destination", "", "Output file for generated code (default: <source>_metamodel.go, e.g., models_metamodel.go)")
+	packageName   = flag.String("packageName", "metamodel", "Package name for generated file (default: metamodel_, optional for custome with pattern <packageName>_, e.g., models)")
+	tag           = flag.String("tag", "json", "Specific tag name to generate, or a comma separated list of json, bson and gorm for one metamodel exposing every name (optional, e.g., json, bson, gorm, json,bson,gorm)")
+	tablePrefix   = flag.String("tablePrefix", "", "Prefix of derived gorm table names, like GORM's NamingStrategy.TablePrefix (optional, e.g., app_)")
+	singularTable = flag.Bool("singularTable", false, "Do not pluralize derived gorm table names, like GORM's NamingStrategy.SingularTable")
+	tableName     = flag.String("tableName", "", "Table name fallback when a struct has no TableName() method (default: <structName>s; a bare name, e.g., users, when the source has a single struct, otherwise pairs, e.g., User=users,Order=orders)")
 )
 
 func main() {
@@ -37,6 +39,10 @@ func main() {
 		PackageName: *packageName,
 		Tag:         *tag,
 		TableName:   *tableName,
+		Naming: generator.NamingStrategy{
+			TablePrefix:   *tablePrefix,
+			SingularTable: *singularTable,
+		},
 	}
 
 	if err := generator.Generate(cfg); err != nil {